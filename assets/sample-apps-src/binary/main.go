@@ -1,9 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"runtime"
 	"strconv"
 )
 
@@ -19,5 +21,59 @@ func main() {
 		fmt.Fprintf(w, "Hello, Homeport!")
 	})
 
+	// Report the runtime environment of the app in a standard JSON format
+	http.HandleFunc("/runtime", func(w http.ResponseWriter, r *http.Request) {
+		vcapApplication := json.RawMessage("null")
+		if value, ok := os.LookupEnv("VCAP_APPLICATION"); ok && json.Valid([]byte(value)) {
+			vcapApplication = json.RawMessage(value)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"language":         "go",
+			"runtime_version":  runtime.Version(),
+			"instance_index":   os.Getenv("CF_INSTANCE_INDEX"),
+			"memory_limit":     os.Getenv("MEMORY_LIMIT"),
+			"stack":            os.Getenv("CF_STACK"),
+			"vcap_application": vcapApplication,
+		})
+	})
+
+	// Crash on purpose to verify that the platform restarts the app
+	http.HandleFunc("/crash", func(w http.ResponseWriter, r *http.Request) {
+		os.Exit(1)
+	})
+
+	// Print the given marker to verify that app logs are streamed
+	http.HandleFunc("/log", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Println(r.URL.Query().Get("marker"))
+	})
+
+	// List the bound service instances and whether they provide credentials,
+	// the credentials themselves are not shown on the public route
+	http.HandleFunc("/services", func(w http.ResponseWriter, r *http.Request) {
+		var services map[string][]struct {
+			Name        string                 `json:"name"`
+			Credentials map[string]interface{} `json:"credentials"`
+		}
+
+		json.Unmarshal([]byte(os.Getenv("VCAP_SERVICES")), &services)
+
+		type instance struct {
+			Name        string `json:"name"`
+			Credentials bool   `json:"credentials"`
+		}
+
+		result := []instance{}
+		for _, instances := range services {
+			for _, entry := range instances {
+				result = append(result, instance{Name: entry.Name, Credentials: len(entry.Credentials) > 0})
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	})
+
 	http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
 }
@@ -0,0 +1,18 @@
+#!/bin/bash
+
+set -euo pipefail
+
+BASEDIR="$(cd "$(dirname "$0")" && pwd)"
+TARGET_DIR="${BASEDIR}/../../sample-apps/$(basename "${BASEDIR}")"
+
+if [[ -f "${TARGET_DIR}/main.go" ]]; then
+  rm "${TARGET_DIR}/main.go"
+fi
+
+if [[ -f "${TARGET_DIR}/websocket.go" ]]; then
+  rm "${TARGET_DIR}/websocket.go"
+fi
+
+if [[ -d "${TARGET_DIR}/vendor/golang.org" ]]; then
+  rm -r "${TARGET_DIR}/vendor/golang.org"
+fi
@@ -0,0 +1,16 @@
+#!/bin/bash
+
+set -euo pipefail
+
+BASEDIR="$(cd "$(dirname "$0")" && pwd)"
+SOURCE_DIR="${BASEDIR}/../../sample-apps/golang"
+TARGET_DIR="${BASEDIR}/../../sample-apps/$(basename "${BASEDIR}")"
+mkdir -p "${TARGET_DIR}"
+
+# The variant only differs in its dependency files, the app itself and its
+# vendored golang.org dependencies are copied from the Golang sample app, so
+# that both serve the same endpoints
+cp "${SOURCE_DIR}/main.go" "${TARGET_DIR}/main.go"
+cp "${SOURCE_DIR}/websocket.go" "${TARGET_DIR}/websocket.go"
+mkdir -p "${TARGET_DIR}/vendor/golang.org"
+cp -R "${SOURCE_DIR}/vendor/golang.org/." "${TARGET_DIR}/vendor/golang.org"
@@ -22,8 +22,19 @@ public class App{
 
                     String data = s.useDelimiter("\\r\\n\\r\\n").next();
                     Matcher get = Pattern.compile("^GET").matcher(data);
+                    Matcher runtime = Pattern.compile("^GET /runtime[ /?]").matcher(data);
 
-                    if(get.find()){
+                    if(runtime.find()){
+                        String message = runtimeInfo();
+
+                        byte[] response = ("HTTP/1.0 200 OK\r\n" +
+                            "Content-Type: application/json\r\n" +
+                            "Date: " + new Date() + "\r\n" +
+                            "Content-length: " + message.getBytes("UTF-8").length + "\r\n\r\n"+
+                            message).getBytes("UTF-8");
+                        out.write(response, 0, response.length);
+
+                    }else if(get.find()){
                         String message = "Hello, Homeport!";
 
                         byte[] response = ("HTTP/1.0 200 OK\r\n" +
@@ -48,4 +59,31 @@ public class App{
             System.err.println("Could not start server: " + tr);
         }
     }
+
+    // Reports the runtime environment of the app in a standard JSON format,
+    // VCAP_APPLICATION is already JSON and is therefore embedded as-is
+    private static String runtimeInfo() {
+        String vcapApplication = env("VCAP_APPLICATION").trim();
+        if (vcapApplication.isEmpty()) {
+            vcapApplication = "null";
+        }
+
+        return "{" +
+            "\"language\":\"java\"," +
+            "\"runtime_version\":" + quote(System.getProperty("java.version")) + "," +
+            "\"instance_index\":" + quote(env("CF_INSTANCE_INDEX")) + "," +
+            "\"memory_limit\":" + quote(env("MEMORY_LIMIT")) + "," +
+            "\"stack\":" + quote(env("CF_STACK")) + "," +
+            "\"vcap_application\":" + vcapApplication +
+            "}";
+    }
+
+    private static String env(String name) {
+        String value = System.getenv(name);
+        return value == null ? "" : value;
+    }
+
+    private static String quote(String value) {
+        return "\"" + value.replace("\\", "\\\\").replace("\"", "\\\"") + "\"";
+    }
 }
\ No newline at end of file
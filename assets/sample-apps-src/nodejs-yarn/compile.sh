@@ -0,0 +1,12 @@
+#!/bin/bash
+
+set -euo pipefail
+
+BASEDIR="$(cd "$(dirname "$0")" && pwd)"
+SOURCE_DIR="${BASEDIR}/../../sample-apps/nodejs"
+TARGET_DIR="${BASEDIR}/../../sample-apps/$(basename "${BASEDIR}")"
+mkdir -p "${TARGET_DIR}"
+
+# The variant only differs in its dependency files, the app itself is copied
+# from the NodeJS sample app, so that both serve the same endpoints
+cp "${SOURCE_DIR}/app.js" "${TARGET_DIR}/app.js"
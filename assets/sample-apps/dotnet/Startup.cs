@@ -2,6 +2,10 @@
 using Microsoft.AspNetCore.Hosting;
 using Microsoft.AspNetCore.Http;
 using Microsoft.Extensions.DependencyInjection;
+using Newtonsoft.Json;
+using Newtonsoft.Json.Linq;
+using System;
+using System.Runtime.InteropServices;
 
 namespace dotnet
 {
@@ -10,6 +14,36 @@ namespace dotnet
 
         public void Configure(IApplicationBuilder app, IHostingEnvironment env)
         {
+            // Report the runtime environment of the app in a standard JSON format
+            app.Map("/runtime", runtime =>
+            {
+                runtime.Run(async (context) =>
+                {
+                    JToken vcapApplication = null;
+                    try
+                    {
+                        vcapApplication = JToken.Parse(Environment.GetEnvironmentVariable("VCAP_APPLICATION") ?? "null");
+                    }
+                    catch (JsonReaderException)
+                    {
+                        // Not running on Cloud Foundry
+                    }
+
+                    var result = new JObject
+                    {
+                        ["language"] = "dotnet",
+                        ["runtime_version"] = RuntimeInformation.FrameworkDescription,
+                        ["instance_index"] = Environment.GetEnvironmentVariable("CF_INSTANCE_INDEX") ?? "",
+                        ["memory_limit"] = Environment.GetEnvironmentVariable("MEMORY_LIMIT") ?? "",
+                        ["stack"] = Environment.GetEnvironmentVariable("CF_STACK") ?? "",
+                        ["vcap_application"] = vcapApplication
+                    };
+
+                    context.Response.ContentType = "application/json";
+                    await context.Response.WriteAsync(result.ToString(Formatting.None));
+                });
+            });
+
             app.Run(async (context) =>
             {
                 await context.Response.WriteAsync("Hello, Homeport!");
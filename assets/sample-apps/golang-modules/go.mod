@@ -0,0 +1,8 @@
+module github.com/homeport/gonut-golang-modules-app
+
+go 1.12
+
+require (
+	github.com/mitchellh/go-homedir v1.1.0
+	golang.org/x/net v0.0.0-20190404232315-eb5bcb51f2a3
+)
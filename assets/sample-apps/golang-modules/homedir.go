@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// Use the vendored dependency to verify that it was part of the build, the
+// rest of the app is copied from the Golang sample app by make prereqs
+func init() {
+	home, err := homedir.Dir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to look up home directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Serving from home directory %s\n", home)
+}
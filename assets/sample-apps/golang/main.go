@@ -1,10 +1,19 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"runtime"
 	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 func main() {
@@ -19,5 +28,97 @@ func main() {
 		fmt.Fprintf(w, "Hello, Homeport!")
 	})
 
-	http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
+	// Report the runtime environment of the app in a standard JSON format
+	http.HandleFunc("/runtime", func(w http.ResponseWriter, r *http.Request) {
+		vcapApplication := json.RawMessage("null")
+		if value, ok := os.LookupEnv("VCAP_APPLICATION"); ok && json.Valid([]byte(value)) {
+			vcapApplication = json.RawMessage(value)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"language":         "go",
+			"runtime_version":  runtime.Version(),
+			"instance_index":   os.Getenv("CF_INSTANCE_INDEX"),
+			"memory_limit":     os.Getenv("MEMORY_LIMIT"),
+			"stack":            os.Getenv("CF_STACK"),
+			"vcap_application": vcapApplication,
+		})
+	})
+
+	// Crash on purpose to verify that the platform restarts the app
+	http.HandleFunc("/crash", func(w http.ResponseWriter, r *http.Request) {
+		os.Exit(1)
+	})
+
+	// Print the given marker to verify that app logs are streamed
+	http.HandleFunc("/log", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Println(r.URL.Query().Get("marker"))
+	})
+
+	// List the bound service instances and whether they provide credentials,
+	// the credentials themselves are not shown on the public route
+	http.HandleFunc("/services", func(w http.ResponseWriter, r *http.Request) {
+		var services map[string][]struct {
+			Name        string                 `json:"name"`
+			Credentials map[string]interface{} `json:"credentials"`
+		}
+
+		json.Unmarshal([]byte(os.Getenv("VCAP_SERVICES")), &services)
+
+		type instance struct {
+			Name        string `json:"name"`
+			Credentials bool   `json:"credentials"`
+		}
+
+		result := []instance{}
+		for _, instances := range services {
+			for _, entry := range instances {
+				result = append(result, instance{Name: entry.Name, Credentials: len(entry.Credentials) > 0})
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	})
+
+	// Forward the request to another app to verify container networking, which
+	// is only enabled for the frontend app of the networking check and limited
+	// to internal routes, so that the app is no open proxy
+	http.HandleFunc("/proxy/", func(w http.ResponseWriter, r *http.Request) {
+		if os.Getenv("GONUT_PROXY_ENABLED") != "true" {
+			http.NotFound(w, r)
+			return
+		}
+
+		target, err := url.Parse(fmt.Sprintf("http://%s", strings.TrimPrefix(r.URL.Path, "/proxy/")))
+		if err != nil || !strings.HasSuffix(target.Hostname(), ".apps.internal") {
+			http.Error(w, "only internal routes are supported", http.StatusForbidden)
+			return
+		}
+
+		client := http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Get(target.String())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	})
+
+	// Echo WebSocket messages to verify WebSocket upgrades through the router
+	http.HandleFunc("/ws", websocketEcho)
+
+	// Report the protocol the request reached the app with, to verify that
+	// the router talks HTTP/2 to the app on routes using the http2 protocol
+	http.HandleFunc("/protocol", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Proto)
+	})
+
+	// Serve HTTP/2 without TLS (h2c) next to HTTP/1.1 on the same port, since
+	// the router does not use TLS for the connection to the app
+	http.ListenAndServe(fmt.Sprintf(":%d", port), h2c.NewHandler(http.DefaultServeMux, &http2.Server{}))
 }
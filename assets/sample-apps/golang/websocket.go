@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opcodeClose = 0x8
+	opcodePing  = 0x9
+	opcodePong  = 0xA
+)
+
+// websocketEcho upgrades the connection to a WebSocket and sends back every
+// message it receives until the client closes the connection
+func websocketEcho(w http.ResponseWriter, r *http.Request) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		http.Error(w, "expected a WebSocket upgrade request", http.StatusBadRequest)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection does not support WebSocket upgrades", http.StatusInternalServerError)
+		return
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + websocketGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		base64.StdEncoding.EncodeToString(sum[:]),
+	)
+
+	if err := rw.Flush(); err != nil {
+		return
+	}
+
+	for {
+		opcode, payload, err := readFrame(rw.Reader)
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case opcodeClose:
+			writeFrame(rw.Writer, opcodeClose, payload)
+			rw.Flush()
+			return
+
+		case opcodePing:
+			writeFrame(rw.Writer, opcodePong, payload)
+
+		default:
+			writeFrame(rw.Writer, opcode, payload)
+		}
+
+		if err := rw.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func readFrame(r *bufio.Reader) (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	if length > 1<<20 {
+		return 0, nil, fmt.Errorf("frame too large")
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return opcode, payload, nil
+}
+
+func writeFrame(w *bufio.Writer, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+
+	switch length := len(payload); {
+	case length < 126:
+		header = append(header, byte(length))
+
+	case length <= 0xFFFF:
+		header = append(header, 126, byte(length>>8), byte(length))
+
+	default:
+		ext := make([]byte, 8)
+		binary.BigEndian.PutUint64(ext, uint64(length))
+		header = append(append(header, 127), ext...)
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	_, err := w.Write(payload)
+	return err
+}
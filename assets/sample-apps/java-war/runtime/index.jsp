@@ -0,0 +1,28 @@
+<%@ page contentType="application/json; charset=UTF-8" trimDirectiveWhitespaces="true" %>
+<%!
+    private static String env(String name) {
+        String value = System.getenv(name);
+        return value == null ? "" : value;
+    }
+
+    private static String quote(String value) {
+        return "\"" + value.replace("\\", "\\\\").replace("\"", "\\\"") + "\"";
+    }
+%>
+<%
+    // Report the runtime environment of the app in a standard JSON format,
+    // VCAP_APPLICATION is already JSON and is therefore embedded as-is
+    String vcapApplication = env("VCAP_APPLICATION").trim();
+    if (vcapApplication.isEmpty()) {
+        vcapApplication = "null";
+    }
+
+    out.print("{" +
+        "\"language\":\"java\"," +
+        "\"runtime_version\":" + quote(System.getProperty("java.version")) + "," +
+        "\"instance_index\":" + quote(env("CF_INSTANCE_INDEX")) + "," +
+        "\"memory_limit\":" + quote(env("MEMORY_LIMIT")) + "," +
+        "\"stack\":" + quote(env("CF_STACK")) + "," +
+        "\"vcap_application\":" + vcapApplication +
+        "}");
+%>
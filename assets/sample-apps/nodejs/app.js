@@ -2,6 +2,65 @@ var port = (process.env.PORT || 8080);
 var http = require('http');
 
 http.createServer(function (req, res) {
+    // Report the runtime environment of the app in a standard JSON format
+    if (req.url === '/runtime') {
+        var vcapApplication = null;
+        try {
+            vcapApplication = JSON.parse(process.env.VCAP_APPLICATION);
+        } catch (err) {
+            // Not running on Cloud Foundry
+        }
+
+        res.writeHead(200, { 'Content-Type': 'application/json' });
+        res.end(JSON.stringify({
+            language: 'nodejs',
+            runtime_version: process.version,
+            instance_index: process.env.CF_INSTANCE_INDEX || '',
+            memory_limit: process.env.MEMORY_LIMIT || '',
+            stack: process.env.CF_STACK || '',
+            vcap_application: vcapApplication
+        }));
+        return;
+    }
+
+    // Crash on purpose to verify that the platform restarts the app
+    if (req.url === '/crash') {
+        process.exit(1);
+    }
+
+    // Print the given marker to verify that app logs are streamed
+    if (req.url.indexOf('/log?') === 0) {
+        console.log(require('url').parse(req.url, true).query.marker);
+        res.writeHead(200, { 'Content-Type': 'text/plain' });
+        res.end();
+        return;
+    }
+
+    // List the bound service instances and whether they provide credentials,
+    // the credentials themselves are not shown on the public route
+    if (req.url === '/services') {
+        var vcapServices = {};
+        try {
+            vcapServices = JSON.parse(process.env.VCAP_SERVICES || '{}');
+        } catch (err) {
+            // No valid service bindings
+        }
+
+        var result = [];
+        Object.keys(vcapServices).forEach(function (label) {
+            vcapServices[label].forEach(function (instance) {
+                result.push({
+                    name: instance.name,
+                    credentials: Object.keys(instance.credentials || {}).length > 0
+                });
+            });
+        });
+
+        res.writeHead(200, { 'Content-Type': 'application/json' });
+        res.end(JSON.stringify(result));
+        return;
+    }
+
     res.writeHead(200, { 'Content-Type': 'text/plain' });
     res.end('Hello, Homeport!\n');
 }).listen(port);
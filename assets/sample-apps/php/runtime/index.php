@@ -0,0 +1,12 @@
+<?php
+// Report the runtime environment of the app in a standard JSON format
+header('Content-Type: application/json');
+echo json_encode(array(
+    'language' => 'php',
+    'runtime_version' => phpversion(),
+    'instance_index' => (string) getenv('CF_INSTANCE_INDEX'),
+    'memory_limit' => (string) getenv('MEMORY_LIMIT'),
+    'stack' => (string) getenv('CF_STACK'),
+    'vcap_application' => json_decode((string) getenv('VCAP_APPLICATION')),
+));
+?>
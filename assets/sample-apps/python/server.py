@@ -1,5 +1,8 @@
-from flask import Flask
+from flask import Flask, jsonify, request
+import json
 import os
+import platform
+import sys
 
 app = Flask(__name__)
 port = int(os.getenv("PORT", 8080))
@@ -8,5 +11,50 @@ port = int(os.getenv("PORT", 8080))
 def hello_world():
     return 'Hello, Homeport!'
 
+@app.route('/runtime')
+def runtime():
+    # Report the runtime environment of the app in a standard JSON format
+    try:
+        vcap_application = json.loads(os.getenv("VCAP_APPLICATION", ""))
+    except ValueError:
+        vcap_application = None
+
+    return jsonify(
+        language='python',
+        runtime_version=platform.python_version(),
+        instance_index=os.getenv("CF_INSTANCE_INDEX", ""),
+        memory_limit=os.getenv("MEMORY_LIMIT", ""),
+        stack=os.getenv("CF_STACK", ""),
+        vcap_application=vcap_application,
+    )
+
+@app.route('/crash')
+def crash():
+    # Crash on purpose to verify that the platform restarts the app
+    os._exit(1)
+
+@app.route('/log')
+def log():
+    # Print the given marker to verify that app logs are streamed
+    sys.stdout.write(request.args.get('marker', '') + '\n')
+    sys.stdout.flush()
+    return ''
+
+@app.route('/services')
+def services():
+    # List the bound service instances and whether they provide credentials,
+    # the credentials themselves are not shown on the public route
+    try:
+        vcap_services = json.loads(os.getenv("VCAP_SERVICES", "{}"))
+    except ValueError:
+        vcap_services = {}
+
+    result = []
+    for instances in vcap_services.values():
+        for instance in instances:
+            result.append({'name': instance.get('name'), 'credentials': bool(instance.get('credentials'))})
+
+    return app.response_class(json.dumps(result), mimetype='application/json')
+
 if __name__ == '__main__':
     app.run(host='0.0.0.0', port=port)
@@ -1,4 +1,53 @@
 require 'sinatra'
+require 'json'
     get '/' do
         "Hello, Homeport!"
-    end
\ No newline at end of file
+    end
+
+    # Report the runtime environment of the app in a standard JSON format
+    get '/runtime' do
+        vcap_application = begin
+            JSON.parse(ENV.fetch('VCAP_APPLICATION', ''))
+        rescue JSON::ParserError
+            nil
+        end
+
+        content_type :json
+        JSON.generate(
+            'language' => 'ruby',
+            'runtime_version' => RUBY_VERSION,
+            'instance_index' => ENV.fetch('CF_INSTANCE_INDEX', ''),
+            'memory_limit' => ENV.fetch('MEMORY_LIMIT', ''),
+            'stack' => ENV.fetch('CF_STACK', ''),
+            'vcap_application' => vcap_application
+        )
+    end
+
+    # Crash on purpose to verify that the platform restarts the app
+    get '/crash' do
+        exit!(1)
+    end
+
+    # Print the given marker to verify that app logs are streamed
+    get '/log' do
+        puts params['marker']
+        $stdout.flush
+        ''
+    end
+
+    # List the bound service instances and whether they provide credentials,
+    # the credentials themselves are not shown on the public route
+    get '/services' do
+        vcap_services = begin
+            JSON.parse(ENV.fetch('VCAP_SERVICES', '{}'))
+        rescue JSON::ParserError
+            {}
+        end
+
+        result = vcap_services.values.flatten.map do |instance|
+            { 'name' => instance['name'], 'credentials' => !(instance['credentials'] || {}).empty? }
+        end
+
+        content_type :json
+        JSON.generate(result)
+    end
@@ -0,0 +1,66 @@
+var port = (process.env.PORT || 8080);
+var sidecarPort = 8081;
+var fs = require('fs');
+var http = require('http');
+
+http.createServer(function (req, res) {
+    // Report the runtime environment of the app in a standard JSON format
+    if (req.url === '/runtime') {
+        var vcapApplication = null;
+        try {
+            vcapApplication = JSON.parse(process.env.VCAP_APPLICATION);
+        } catch (err) {
+            // Not running on Cloud Foundry
+        }
+
+        res.writeHead(200, { 'Content-Type': 'application/json' });
+        res.end(JSON.stringify({
+            language: 'nodejs',
+            runtime_version: process.version,
+            instance_index: process.env.CF_INSTANCE_INDEX || '',
+            memory_limit: process.env.MEMORY_LIMIT || '',
+            stack: process.env.CF_STACK || '',
+            vcap_application: vcapApplication
+        }));
+        return;
+    }
+
+    // List the command lines of all processes in the container, so that the
+    // sidecar process can be found without contacting it
+    if (req.url === '/processes') {
+        var commands = [];
+        fs.readdirSync('/proc').forEach(function (entry) {
+            if (!/^[0-9]+$/.test(entry)) {
+                return;
+            }
+
+            try {
+                var command = fs.readFileSync('/proc/' + entry + '/cmdline', 'utf8').split('\0').join(' ').trim();
+                if (command.length > 0) {
+                    commands.push(command);
+                }
+            } catch (err) {
+                // The process ended in the meantime
+            }
+        });
+
+        res.writeHead(200, { 'Content-Type': 'application/json' });
+        res.end(JSON.stringify(commands));
+        return;
+    }
+
+    // Forward the request to the sidecar process running in the same container
+    if (req.url === '/sidecar') {
+        http.get({ host: 'localhost', port: sidecarPort, path: '/' }, function (sidecarRes) {
+            res.writeHead(sidecarRes.statusCode, { 'Content-Type': 'application/json' });
+            sidecarRes.pipe(res);
+        }).on('error', function (err) {
+            res.writeHead(502, { 'Content-Type': 'text/plain' });
+            res.end(err.message + '\n');
+        });
+        return;
+    }
+
+    res.writeHead(200, { 'Content-Type': 'text/plain' });
+    res.end('Hello, Homeport!\n');
+}).listen(port);
@@ -0,0 +1,13 @@
+var port = 8081;
+var http = require('http');
+
+// Answer requests of the main app over localhost with the details of the
+// sidecar process, so that the main app can show that it reaches the sidecar
+http.createServer(function (req, res) {
+    res.writeHead(200, { 'Content-Type': 'application/json' });
+    res.end(JSON.stringify({
+        name: 'gonut-sidecar',
+        pid: process.pid,
+        uptime: process.uptime()
+    }));
+}).listen(port, 'localhost');
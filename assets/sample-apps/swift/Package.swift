@@ -2,6 +2,31 @@
 // The swift-tools-version declares the minimum version of Swift required to build this package.
 
 import PackageDescription
+import Foundation
+
+// The Swift compiler version cannot be read at runtime, so the output of
+// `swift --version` is captured while the package is built and written into
+// Sources/App/CompilerVersion.swift before the sources of the app are compiled
+let packageDirectory = URL(fileURLWithPath: #file).deletingLastPathComponent()
+let compilerVersionFile = packageDirectory.appendingPathComponent("Sources/App/CompilerVersion.swift")
+
+let swiftVersionProcess = Process()
+let swiftVersionPipe = Pipe()
+swiftVersionProcess.launchPath = "/usr/bin/env"
+swiftVersionProcess.arguments = ["swift", "--version"]
+swiftVersionProcess.standardOutput = swiftVersionPipe
+swiftVersionProcess.standardError = swiftVersionPipe
+swiftVersionProcess.launch()
+swiftVersionProcess.waitUntilExit()
+
+// The first line looks like "Swift version 5.9.2 (swift-5.9.2-RELEASE)"
+let swiftVersionOutput = String(data: swiftVersionPipe.fileHandleForReading.readDataToEndOfFile(), encoding: .utf8) ?? ""
+let swiftVersionWords = (swiftVersionOutput.components(separatedBy: "\n").first ?? "").components(separatedBy: " ")
+if swiftVersionProcess.terminationStatus == 0,
+    let index = swiftVersionWords.index(of: "version"), index + 1 < swiftVersionWords.count {
+    let source = "// Generated from `swift --version` when the package is built, do not edit\nlet compilerVersion = \"\(swiftVersionWords[index + 1])\"\n"
+    try? source.write(to: compilerVersionFile, atomically: true, encoding: .utf8)
+}
 
 let package = Package(
     name: "johnny-5",
@@ -13,5 +13,31 @@ endpoint.get("/"){
     next()
 }
 
+// Report the runtime environment of the app in a standard JSON format
+endpoint.get("/runtime"){
+    request, response, next in
+    let environment = ProcessInfo.processInfo.environment
+
+    var vcapApplication: Any = NSNull()
+    if let data = environment["VCAP_APPLICATION"]?.data(using: .utf8),
+        let parsed = try? JSONSerialization.jsonObject(with: data, options: []) {
+        vcapApplication = parsed
+    }
+
+    let result: [String: Any] = [
+        "language": "swift",
+        "runtime_version": compilerVersion,
+        "instance_index": environment["CF_INSTANCE_INDEX"] ?? "",
+        "memory_limit": environment["MEMORY_LIMIT"] ?? "",
+        "stack": environment["CF_STACK"] ?? "",
+        "vcap_application": vcapApplication,
+    ]
+
+    let body = try JSONSerialization.data(withJSONObject: result, options: [])
+    response.headers["Content-Type"] = "application/json"
+    response.send(data: body)
+    next()
+}
+
 Kitura.addHTTPServer(onPort: 8080, with: endpoint)
 Kitura.run()
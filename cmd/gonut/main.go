@@ -25,19 +25,14 @@ import (
 	"os/signal"
 	"syscall"
 
-	"github.com/gonvenience/term"
 	"github.com/homeport/gonut/internal/gonut/cmd"
 )
 
 func main() {
-	signals := make(chan os.Signal)
+	signals := make(chan os.Signal, 2)
 	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
 
-	go func() {
-		<-signals
-		term.ShowCursor()
-		os.Exit(1)
-	}()
+	go cmd.HandleInterrupts(signals)
 
 	cmd.Execute()
 }
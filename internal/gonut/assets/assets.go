@@ -35,10 +35,22 @@ type ProviderInterface interface {
 	// @pgl(asset=/assets/sample-apps/golang/&compressor=tar)
 	GoSampleApp() (directory files.Directory, e error)
 
+	// GoModulesSampleApp returns the directory containing the Golang sample app using Go modules and vendoring
+	// @pgl(asset=/assets/sample-apps/golang-modules/&compressor=tar)
+	GoModulesSampleApp() (directory files.Directory, e error)
+
 	// PythonSampleApp returns the directory containing the Python sample app
 	// @pgl(asset=/assets/sample-apps/python/&compressor=tar)
 	PythonSampleApp() (directory files.Directory, e error)
 
+	// PythonPipenvSampleApp returns the directory containing the Python sample app using Pipenv
+	// @pgl(asset=/assets/sample-apps/python-pipenv/&compressor=tar)
+	PythonPipenvSampleApp() (directory files.Directory, e error)
+
+	// PythonCondaSampleApp returns the directory containing the Python sample app using Conda
+	// @pgl(asset=/assets/sample-apps/python-conda/&compressor=tar)
+	PythonCondaSampleApp() (directory files.Directory, e error)
+
 	// PHPSampleApp returns the directory containing the PHP sample app
 	// @pgl(asset=/assets/sample-apps/php/&compressor=tar)
 	PHPSampleApp() (directory files.Directory, e error)
@@ -55,6 +67,10 @@ type ProviderInterface interface {
 	// @pgl(asset=/assets/sample-apps/nodejs/&compressor=tar)
 	NodeJSSampleApp() (directory files.Directory, e error)
 
+	// NodeJSYarnSampleApp returns the directory containing the NodeJS sample app using Yarn
+	// @pgl(asset=/assets/sample-apps/nodejs-yarn/&compressor=tar)
+	NodeJSYarnSampleApp() (directory files.Directory, e error)
+
 	// RubySampleApp returns the directory containing the Ruby sample app
 	// @pgl(asset=/assets/sample-apps/ruby/&compressor=tar)
 	RubySampleApp() (directory files.Directory, e error)
@@ -70,4 +86,24 @@ type ProviderInterface interface {
 	// JavaSampleApp returns the directory containing the Java sample app
 	// @pgl(asset=/assets/sample-apps/java/&compressor=tar)
 	JavaSampleApp() (directory files.Directory, e error)
+
+	// JavaWarSampleApp returns the directory containing the Java web application sample app for Tomcat
+	// @pgl(asset=/assets/sample-apps/java-war/&compressor=tar)
+	JavaWarSampleApp() (directory files.Directory, e error)
+
+	// RSampleApp returns the directory containing the R sample app
+	// @pgl(asset=/assets/sample-apps/r/&compressor=tar)
+	RSampleApp() (directory files.Directory, e error)
+
+	// NginxSampleApp returns the directory containing the Nginx sample app
+	// @pgl(asset=/assets/sample-apps/nginx/&compressor=tar)
+	NginxSampleApp() (directory files.Directory, e error)
+
+	// HWCSampleApp returns the directory containing the HWC sample app for Windows stacks
+	// @pgl(asset=/assets/sample-apps/hwc/&compressor=tar)
+	HWCSampleApp() (directory files.Directory, e error)
+
+	// SidecarSampleApp returns the directory containing the NodeJS sample app with a sidecar
+	// @pgl(asset=/assets/sample-apps/sidecar/&compressor=tar)
+	SidecarSampleApp() (directory files.Directory, e error)
 }
@@ -23,6 +23,7 @@ package cf
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -40,8 +41,11 @@ import (
 	"github.com/mitchellh/go-homedir"
 )
 
-// PushApp performs a Cloud Foundry CLI based push operation
-func PushApp(caption string, appName string, directory files.Directory, cleanupSetting AppCleanupSetting, noPingSetting bool) (*PushReport, error) {
+// PushApp performs a Cloud Foundry CLI based push operation. When the context
+// is cancelled or a timeout is reached, the running CF CLI call is stopped and
+// the configured cleanup runs with its own context that is bound by the
+// cleanup timeout.
+func PushApp(parentCtx context.Context, caption string, appName string, directory files.Directory, settings PushSettings) (*PushReport, error) {
 	if !isLoggedIn() {
 		return nil, nok.Errorf(
 			fmt.Sprintf("failed to push application %s to Cloud Foundry", appName),
@@ -57,13 +61,36 @@ func PushApp(caption string, appName string, directory files.Directory, cleanupS
 	}
 
 	report := PushReport{
-		AppName: appName,
+		AppName:     appName,
+		DockerImage: settings.DockerImage,
 	}
 
-	err := runWithTempDir(func(path string) error {
-		// Changed during each step of the verification process
-		step := "Ramp-up"
+	var (
+		totalCtx    context.Context
+		cancelTotal context.CancelFunc
+	)
+
+	if settings.TotalTimeout > 0 {
+		totalCtx, cancelTotal = context.WithTimeout(parentCtx, settings.TotalTimeout)
+	} else {
+		totalCtx, cancelTotal = context.WithCancel(parentCtx)
+	}
+	defer cancelTotal()
+
+	// The push context is cancelled when any of the phase timeouts is reached
+	ctx, cancel := context.WithCancel(totalCtx)
+	defer cancel()
+
+	// Changed during each step of the verification process
+	step := "Ramp-up"
 
+	phases := newPhaseTracker(step, cancel, map[string]time.Duration{
+		"Staging":  settings.StagingTimeout,
+		"Starting": settings.StartTimeout,
+	})
+	defer phases.stop()
+
+	err := runWithTempDir(func(path string) error {
 		spinner := wait.NewProgressIndicator("*%s*, DimGray{%s}", caption, step)
 		spinner.Start()
 		defer spinner.Stop()
@@ -75,6 +102,7 @@ func PushApp(caption string, appName string, directory files.Directory, cleanupS
 				if text := strings.Trim(update, " "); len(text) > 0 {
 					if result := report.ParseUpdate(text); result != "" {
 						step = result
+						phases.enter(step)
 					}
 
 					spinner.SetText("*%s*, DimGray{%s} - %s",
@@ -86,14 +114,19 @@ func PushApp(caption string, appName string, directory files.Directory, cleanupS
 			}
 		}()
 
-		if err := files.WriteToDisk(directory, path, true); err != nil {
-			return nok.Errorf(
-				fmt.Sprintf("failed to push application %s to Cloud Foundry", appName),
-				fmt.Sprintf("An error occurred while trying to write the sample app files to disk: %v", err),
-			)
+		// Docker image based pushes do not have any sample app files
+		pathToSampleApp := path
+		if directory != nil {
+			if err := files.WriteToDisk(directory, path, true); err != nil {
+				return nok.Errorf(
+					fmt.Sprintf("failed to push application %s to Cloud Foundry", appName),
+					fmt.Sprintf("An error occurred while trying to write the sample app files to disk: %v", err),
+				)
+			}
+
+			pathToSampleApp = filepath.Join(path, directory.AbsolutePath().String())
 		}
 
-		pathToSampleApp := filepath.Join(path, directory.AbsolutePath().String())
 		if err := os.Chdir(pathToSampleApp); err != nil {
 			return nok.Errorf(
 				fmt.Sprintf("failed to push application %s to Cloud Foundry", appName),
@@ -101,27 +134,74 @@ func PushApp(caption string, appName string, directory files.Directory, cleanupS
 			)
 		}
 
+		if !settings.Overrides.IsEmpty() {
+			manifestPath := filepath.Join(pathToSampleApp, "manifest.yml")
+			if err := ApplyManifestOverrides(manifestPath, appName, settings.Overrides); err != nil {
+				return nok.Errorf(
+					fmt.Sprintf("failed to push application %s to Cloud Foundry", appName),
+					fmt.Sprintf("An error occurred while trying to apply the manifest overrides to %s: %v", manifestPath, err),
+				)
+			}
+		}
+
+		// Sidecars are taken out of the manifest and created using the API
+		var sidecars []SidecarDefinition
+		if settings.Sidecars {
+			manifestPath := filepath.Join(pathToSampleApp, "manifest.yml")
+			sidecarDefinitions, err := ExtractManifestSidecars(manifestPath)
+			if err != nil {
+				return nok.Errorf(
+					fmt.Sprintf("failed to push application %s to Cloud Foundry", appName),
+					fmt.Sprintf("An error occurred while trying to read the sidecars from %s: %v", manifestPath, err),
+				)
+			}
+
+			sidecars = sidecarDefinitions
+		}
+
 		// If cleanup setting is set to always, make sure to run the delete app
 		// CF CLI call no matter what happens next.
-		if cleanupSetting == Always {
-			defer cf(updates, "delete", appName, "-r", "-f")
+		if settings.CleanupSetting == Always {
+			defer func() {
+				cleanupCtx, cancel := CleanupContext(settings.CleanupTimeout)
+				defer cancel()
+
+				cf(cleanupCtx, updates, "delete", appName, "-r", "-f")
+			}()
 		}
 
 		// Note the timestamp when the push starts
 		report.InitStart = time.Now()
 
-		if output, err := cf(updates, "push", appName); err != nil {
+		pushArgs := []string{"push", appName}
+		for _, buildpack := range settings.Buildpacks {
+			pushArgs = append(pushArgs, "-b", buildpack)
+		}
+
+		if len(settings.DockerImage) > 0 {
+			pushArgs = append(pushArgs, "--docker-image", settings.DockerImage)
+			if len(settings.DockerUsername) > 0 {
+				pushArgs = append(pushArgs, "--docker-username", settings.DockerUsername)
+			}
+		}
+
+		output, err := cf(ctx, updates, pushArgs...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
 			caption := fmt.Sprintf("failed to push application %s to Cloud Foundry", appName)
 
 			// Redefine caption in case Cloud Foundry gives us staging failure details
-			if app, appDetailsError := getApp(appName); appDetailsError == nil {
+			if app, appDetailsError := getApp(ctx, appName); appDetailsError == nil {
 				if app.Entity.StagingFailedDescription != nil && app.Entity.StagingFailedReason != nil {
 					caption = fmt.Sprintf("%s (%s)", app.Entity.StagingFailedDescription, app.Entity.StagingFailedReason)
 				}
 			}
 
 			// Try to get recent app logs to be appended to the error output
-			if recentLogs, err := cf(nil, "logs", appName, "--recent"); err == nil {
+			if recentLogs, err := cf(ctx, nil, "logs", appName, "--recent"); err == nil {
 				output = fmt.Sprintf("%s\n\nApplication logs:\n%s",
 					output,
 					recentLogs,
@@ -133,50 +213,245 @@ func PushApp(caption string, appName string, directory files.Directory, cleanupS
 
 		// Note the timestamp when the push has finished
 		report.PushEnd = time.Now()
+		phases.enter("Verifying")
+
+		// The route of the app is taken from the push output, so that the route
+		// polling starts right away, and only looked up in Cloud Foundry if the
+		// output does not list it. It is shared by all checks that send
+		// requests to the app.
+		appRoute, _ := PushedAppRoute(output)
+		lookUpAppRoute := func() error {
+			if len(appRoute) > 0 {
+				return nil
+			}
+
+			route, err := getAppRoute(ctx, appName)
+			if err != nil {
+				return nok.Errorf(
+					fmt.Sprintf("failed to get url of application %s from Cloud Foundry", appName),
+					err.Error(),
+				)
+			}
+
+			appRoute = route
+			return nil
+		}
+
+		// If pinging is not disabled, poll the pushed app until its route
+		// answers to measure how long the route registration takes
+		if !settings.NoPing {
+			if err := lookUpAppRoute(); err != nil {
+				return err
+			}
+
+			phases.enter("Routing")
+			spinner.SetText("*%s*, DimGray{%s} - %s", caption, "Routing", appRoute)
+
+			statusCode, err := waitForRouteRegistration(ctx, appName, appRoute)
+			report.StatusCode = statusCode
+			if err != nil {
+				return err
+			}
+
+			report.RouteRegisteredEnd = time.Now()
+			phases.enter("Verifying")
+		}
+
+		// Ask the sample app which runtime the buildpack installed, the details
+		// are informational only and a failed request does not fail the push
+		if len(appRoute) > 0 && settings.RuntimeInfo {
+			if runtime, err := getRuntimeInfo(ctx, appRoute); err == nil {
+				report.Runtime = runtime
+			}
+		}
 
 		// Gather details about the buildpack used for the app
-		if buildpack, err := getBuildpack(appName); err == nil {
+		if buildpack, err := getBuildpack(ctx, appName); err == nil {
 			report.buildpack = buildpack
 		}
 
+		// Gather details about all buildpacks used in a buildpack chain
+		if len(settings.Buildpacks) > 1 {
+			if buildpacks, err := getDropletBuildpacks(ctx, appName); err == nil {
+				report.buildpacks = buildpacks
+			}
+		}
+
 		// Gather details about the stack used for the app
-		if stack, err := getStack(appName); err == nil {
+		if stack, err := getStack(ctx, appName); err == nil {
 			report.stack = stack
 		}
 
-		// If pinging is not disabled, ping the pushed app to
-		// determine its statuscode.
-		if !noPingSetting {
-			// Get public URL of application
-			appRoute, err := getAppRoute(appName)
+		// Verify that all instances are running if more than one is requested
+		if settings.Overrides.Instances > 1 {
+			instances, err := waitForRunningInstances(ctx, appName, settings.Overrides.Instances, settings.StartTimeout)
+			report.Instances = instances
 			if err != nil {
-				return nok.Errorf(
-					fmt.Sprintf("failed to get url of application %s from Cloud Foundry", appName),
-					err.Error(),
-				)
+				return err
 			}
+		}
 
-			if statusCode, err := getAppStatusCode(appRoute); err == nil {
-				if statusCode != http.StatusOK {
-					return nok.Errorf(
-						fmt.Sprintf("application %s returned a non-ok statuscode %d", appName, statusCode),
-						"The application did not return the statuscode 200. Please try to push the same sample application again.",
-					)
-				}
-				report.StatusCode = statusCode
+		// Verify that the metrics pipeline reports the container resource usage
+		if settings.ContainerStats {
+			stats, err := collectContainerStats(ctx, appName)
+			report.Stats = stats
+			if err != nil {
+				return err
+			}
+		}
 
-			} else {
-				return nok.Errorf(
-					fmt.Sprintf("unable to ping application %s with route %s", appName, appRoute),
-					err.Error(),
-				)
+		// Ping each instance individually in case there are multiple
+		if len(appRoute) > 0 && len(report.Instances) > 1 {
+			if err := probeAppInstances(ctx, appName, appRoute, report.Instances); err != nil {
+				return err
+			}
+		}
+
+		// Check WebSocket upgrades and HTTP/2 through the router
+		if len(appRoute) > 0 && settings.Protocols {
+			phases.enter("Protocols")
+			spinner.SetText("*%s*, DimGray{%s}", caption, "Protocols")
+
+			protocolReport, err := verifyProtocols(ctx, appName, appRoute)
+			report.Protocols = protocolReport
+			if err != nil {
+				return err
+			}
+
+			phases.enter("Verifying")
+		}
+
+		// Send steady traffic to the app route to check the throughput
+		if len(appRoute) > 0 && !settings.Load.IsEmpty() {
+			phases.enter("Load")
+			spinner.SetText("*%s*, DimGray{%s} - %s at %d rps", caption, "Load", settings.Load.Duration, settings.Load.Rate)
+
+			loadReport, err := runLoadProbe(ctx, appName, appRoute, settings.Load)
+			report.Load = loadReport
+			if err != nil {
+				return err
+			}
+
+			phases.enter("Verifying")
+		}
+
+		// Run a command in the app container to verify SSH access
+		if settings.SSHCheck {
+			phases.enter("SSH")
+			spinner.SetText("*%s*, DimGray{%s}", caption, "SSH")
+
+			sshReport, err := verifySSHAccess(ctx, appName)
+			report.SSH = sshReport
+			if err != nil {
+				return err
+			}
+
+			phases.enter("Verifying")
+		}
+
+		// Run a one-off task on the droplet of the app
+		if settings.RunTask {
+			phases.enter("Task")
+			spinner.SetText("*%s*, DimGray{%s}", caption, "Task")
+
+			taskReport, err := runTask(ctx, appName)
+			report.Task = taskReport
+			if err != nil {
+				return err
+			}
+
+			phases.enter("Verifying")
+		}
+
+		// Verify that the sidecars run next to the main process
+		if len(sidecars) > 0 {
+			phases.enter("Sidecars")
+			spinner.SetText("*%s*, DimGray{%s}", caption, "Sidecars")
+
+			if err := lookUpAppRoute(); err != nil {
+				return err
+			}
+
+			sidecarReport, err := verifySidecars(ctx, appName, appRoute, sidecars)
+			report.Sidecar = sidecarReport
+			if err != nil {
+				return err
+			}
+
+			phases.enter("Verifying")
+		}
+
+		// Measure the time it takes until a log line shows up in the log stream
+		if settings.LogStreaming {
+			phases.enter("Logs")
+			spinner.SetText("*%s*, DimGray{%s}", caption, "Logs")
+
+			if err := lookUpAppRoute(); err != nil {
+				return err
+			}
+
+			logReport, err := verifyLogStreaming(ctx, appName, appRoute)
+			report.Logs = logReport
+			if err != nil {
+				return err
+			}
+
+			phases.enter("Verifying")
+		}
+
+		// Crash the app on purpose and measure how long it takes to recover
+		if settings.CrashRecovery {
+			if err := lookUpAppRoute(); err != nil {
+				return err
+			}
+
+			recoveryTime, err := verifyCrashRecovery(ctx, appName, appRoute, settings.CrashRecoveryThreshold)
+			report.CrashRecoveryTime = recoveryTime
+			report.CrashRecoveryThreshold = settings.CrashRecoveryThreshold
+			if err != nil {
+				return err
+			}
+		}
+
+		// Create and bind a marketplace service to verify the broker path
+		if len(settings.ServiceOffering) > 0 {
+			if err := lookUpAppRoute(); err != nil {
+				return err
+			}
+
+			serviceReport, err := runServiceBindingCheck(ctx, spinner, phases, caption, appName, appRoute, settings)
+			report.Service = serviceReport
+			if err != nil {
+				return err
+			}
+		}
+
+		// Run the additional lifecycle operations on the pushed app
+		for _, operation := range settings.LifecycleOperations {
+			lifecycleReport, err := runLifecycleOperation(ctx, spinner, phases, caption, appName, operation, settings.Overrides.Instances, settings.StartTimeout)
+			report.Lifecycle = append(report.Lifecycle, lifecycleReport)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Redeploy the app using the rolling strategy and verify it stays available
+		if settings.RollingDeployment {
+			if err := lookUpAppRoute(); err != nil {
+				return err
+			}
+
+			rollingReport, err := runRollingDeployment(ctx, spinner, phases, caption, appName, appRoute, pushArgs)
+			report.RollingDeployment = rollingReport
+			if err != nil {
+				return err
 			}
 		}
 
 		// If cleanup setting is set to OnSuccess, run the app removal and
 		// report any issues that might come up during that operation.
-		if cleanupSetting == OnSuccess {
-			if output, err := cf(updates, "delete", appName, "-r", "-f"); err != nil {
+		if settings.CleanupSetting == OnSuccess {
+			if output, err := cf(ctx, updates, "delete", appName, "-r", "-f"); err != nil {
 				return nok.Errorf(
 					fmt.Sprintf("failed to delete application %s from Cloud Foundry", appName),
 					output,
@@ -187,11 +462,16 @@ func PushApp(caption string, appName string, directory files.Directory, cleanupS
 		return nil
 	})
 
+	// Name the phase in which the push was stopped in case it was cancelled
+	if err != nil && ctx.Err() != nil {
+		err = phases.contextError(appName, totalCtx, settings.TotalTimeout)
+	}
+
 	return &report, err
 }
 
 // DeleteApps iterates over the apps in the slice and delete them
-func DeleteApps(apps []AppDetails) error {
+func DeleteApps(ctx context.Context, apps []AppDetails) error {
 	caption := "Cleaning Up"
 	spinner := wait.NewProgressIndicator("*%s*", caption)
 	spinner.Start()
@@ -211,7 +491,7 @@ func DeleteApps(apps []AppDetails) error {
 	}()
 
 	for _, app := range apps {
-		if err := deleteApp(updates, app); err != nil {
+		if err := deleteApp(ctx, updates, app); err != nil {
 			return err
 		}
 	}
@@ -220,22 +500,55 @@ func DeleteApps(apps []AppDetails) error {
 
 // HasBuildpack returns true if Cloud Foundry reports that a buildpack with the
 // given name exists in the list of installed buildpacks
-func HasBuildpack(buildpackName string) (bool, error) {
-	buildpacks, err := getBuildpacks()
+func HasBuildpack(ctx context.Context, buildpackName string) (bool, error) {
+	missing, err := MissingBuildpacks(ctx, []string{buildpackName})
 	if err != nil {
 		return false, err
 	}
 
+	return len(missing) == 0, nil
+}
+
+// MissingBuildpacks returns the names of the given buildpacks that are not in
+// the list of installed buildpacks
+func MissingBuildpacks(ctx context.Context, buildpackNames []string) ([]string, error) {
+	buildpacks, err := getBuildpacks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := map[string]struct{}{}
 	for _, buildpack := range buildpacks {
-		if buildpack.Entity.Name == buildpackName {
-			return true, nil
+		installed[buildpack.Entity.Name] = struct{}{}
+	}
+
+	missing := []string{}
+	for _, name := range buildpackNames {
+		if _, ok := installed[name]; !ok {
+			missing = append(missing, name)
 		}
 	}
 
-	return false, nil
+	return missing, nil
 }
 
-func deleteApp(updates chan string, app AppDetails) error {
+// IsFeatureFlagEnabled returns true if the Cloud Foundry feature flag with the
+// given name is enabled
+func IsFeatureFlagEnabled(ctx context.Context, name string) (bool, error) {
+	result, err := cf(ctx, nil, "curl", fmt.Sprintf("/v2/config/feature_flags/%s", name))
+	if err != nil {
+		return false, err
+	}
+
+	var featureFlag FeatureFlagDetails
+	if err := json.Unmarshal([]byte(result), &featureFlag); err != nil {
+		return false, err
+	}
+
+	return featureFlag.Enabled, nil
+}
+
+func deleteApp(ctx context.Context, updates chan string, app AppDetails) error {
 	if !isLoggedIn() {
 		return nok.Errorf(
 			fmt.Sprintf("failed to delete application %s", app.Entity.Name),
@@ -250,7 +563,7 @@ func deleteApp(updates chan string, app AppDetails) error {
 		)
 	}
 
-	if _, err := cf(updates, "delete", app.Entity.Name, "-r", "-f"); err != nil {
+	if _, err := cf(ctx, updates, "delete", app.Entity.Name, "-r", "-f"); err != nil {
 		return err
 	}
 
@@ -291,8 +604,18 @@ func getOrgAndSpaceNamesFromConfig() (string, string, error) {
 	return config.OrganizationFields.Name, config.SpaceFields.Name, nil
 }
 
+// getCloudFoundryHome returns the directory the Cloud Foundry CLI uses for its
+// configuration, which is CF_HOME if set and the user home directory otherwise
+func getCloudFoundryHome() (string, error) {
+	if cfHome, ok := os.LookupEnv("CF_HOME"); ok && len(cfHome) > 0 {
+		return cfHome, nil
+	}
+
+	return homedir.Dir()
+}
+
 func getCloudFoundryConfig() (*CloudFoundryConfig, error) {
-	path, err := homedir.Dir()
+	path, err := getCloudFoundryHome()
 	if err != nil {
 		return nil, err
 	}
@@ -310,19 +633,19 @@ func getCloudFoundryConfig() (*CloudFoundryConfig, error) {
 	return &config, nil
 }
 
-func getApp(appName string) (*AppDetails, error) {
-	appGUID, err := cfAppGUID(appName)
+func getApp(ctx context.Context, appName string) (*AppDetails, error) {
+	appGUID, err := cfAppGUID(ctx, appName)
 	if err != nil {
 		return nil, err
 	}
 
-	return cfCurlAppByGUID(appGUID)
+	return cfCurlAppByGUID(ctx, appGUID)
 }
 
 // getAppRoute returns the public URL of the application
 // using its name and the Cloud Foundry host domain.
-func getAppRoute(appName string) (string, error) {
-	domain, err := getDomain(appName)
+func getAppRoute(ctx context.Context, appName string) (string, error) {
+	domain, err := getDomain(ctx, appName)
 	if err != nil {
 		return "", err
 	}
@@ -332,8 +655,23 @@ func getAppRoute(appName string) (string, error) {
 
 // getAppStatusCode sends a GET request to the given URL
 // and returns the statuscode.
-func getAppStatusCode(appRoute string) (int, error) {
-	resp, err := http.Get(appRoute)
+func getAppStatusCode(ctx context.Context, appRoute string) (int, error) {
+	return getAppStatusCodeWithHeader(ctx, appRoute, nil)
+}
+
+// getAppStatusCodeWithHeader sends a GET request with the provided additional
+// header to the given URL and returns the statuscode.
+func getAppStatusCodeWithHeader(ctx context.Context, appRoute string, header http.Header) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, appRoute, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return 0, err
 	}
@@ -343,7 +681,7 @@ func getAppStatusCode(appRoute string) (int, error) {
 }
 
 // GetApps gets all Apps of the targeted org and space
-func GetApps() ([]AppDetails, error) {
+func GetApps(ctx context.Context) ([]AppDetails, error) {
 	if !isLoggedIn() {
 		return nil, nok.Errorf(
 			"failed to get applications",
@@ -358,19 +696,49 @@ func GetApps() ([]AppDetails, error) {
 		)
 	}
 
-	return cfCurlOrgsSpaceApps()
+	return cfCurlOrgsSpaceApps(ctx)
+}
+
+func getBuildpack(ctx context.Context, appName string) (*BuildpackDetails, error) {
+	app, err := getApp(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfCurlBuildpackByGUID(ctx, app.Entity.DetectedBuildpackGUID)
 }
 
-func getBuildpack(appName string) (*BuildpackDetails, error) {
-	app, err := getApp(appName)
+// getDropletBuildpacks returns the names of all buildpacks that were used to
+// stage the current droplet of the app, in the order they were applied
+func getDropletBuildpacks(ctx context.Context, appName string) ([]string, error) {
+	appGUID, err := cfAppGUID(ctx, appName)
 	if err != nil {
 		return nil, err
 	}
 
-	return cfCurlBuildpackByGUID(app.Entity.DetectedBuildpackGUID)
+	droplet, err := cfCurlCurrentDroplet(ctx, appGUID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(droplet.Buildpacks))
+	for _, buildpack := range droplet.Buildpacks {
+		name := buildpack.Name
+		if len(buildpack.BuildpackName) > 0 {
+			name = buildpack.BuildpackName
+		}
+
+		if len(buildpack.Version) > 0 {
+			name = fmt.Sprintf("%s (%s)", name, buildpack.Version)
+		}
+
+		result = append(result, name)
+	}
+
+	return result, nil
 }
 
-func getBuildpacks() ([]BuildpackDetails, error) {
+func getBuildpacks(ctx context.Context) ([]BuildpackDetails, error) {
 	result := []BuildpackDetails{}
 	nextURL := "/v2/buildpacks?results-per-page=10"
 
@@ -379,7 +747,7 @@ func getBuildpacks() ([]BuildpackDetails, error) {
 			break
 		}
 
-		output, err := cf(nil, "curl", nextURL)
+		output, err := cf(ctx, nil, "curl", nextURL)
 		if err != nil {
 			return nil, err
 		}
@@ -396,33 +764,72 @@ func getBuildpacks() ([]BuildpackDetails, error) {
 	return result, nil
 }
 
-func getStack(appName string) (*StackDetails, error) {
-	app, err := getApp(appName)
+// GetStacks returns all stacks that are available in Cloud Foundry
+func GetStacks(ctx context.Context) ([]StackDetails, error) {
+	result := []StackDetails{}
+	nextURL := "/v2/stacks?results-per-page=10"
+
+	for {
+		if len(nextURL) == 0 {
+			break
+		}
+
+		output, err := cf(ctx, nil, "curl", nextURL)
+		if err != nil {
+			return nil, err
+		}
+
+		var stacksPage StacksPage
+		if err := json.Unmarshal([]byte(output), &stacksPage); err != nil {
+			return nil, err
+		}
+
+		result = append(result, stacksPage.Resources...)
+		nextURL = stacksPage.NextURL
+	}
+
+	return result, nil
+}
+
+// WindowsStack returns the name of the first Windows stack in the list of
+// stacks, or an empty string if there is none
+func WindowsStack(stacks []StackDetails) string {
+	for _, stack := range stacks {
+		if strings.HasPrefix(stack.Entity.Name, "windows") {
+			return stack.Entity.Name
+		}
+	}
+
+	return ""
+}
+
+func getStack(ctx context.Context, appName string) (*StackDetails, error) {
+	app, err := getApp(ctx, appName)
 	if err != nil {
 		return nil, err
 	}
 
-	return cfCurlStackURL(app.Entity.StackURL)
+	return cfCurlStackURL(ctx, app.Entity.StackURL)
 }
 
 // getDomain returns the current Cloud Foundry host
 // domain of the application.
-func getDomain(appName string) (string, error) {
-	app, err := getApp(appName)
+func getDomain(ctx context.Context, appName string) (string, error) {
+	app, err := getApp(ctx, appName)
 	if err != nil {
 		return "", err
 	}
 
 	// Get route details of the application
 	routesURL := app.Entity.RoutesURL
-	routePage, err := cfCurlRouteURL(routesURL)
+	routePage, err := cfCurlRouteURL(ctx, routesURL)
 	if err != nil {
 		return "", err
 	}
 
 	// Get domain details of the application
 	domainGUID := routePage.Resources[0].Entity.DomainGUID // Resources route always contains only one element
-	domainDetails, err := cfCurlDomainByGUID(domainGUID)
+	domainDetails, err := cfCurlDomainByGUID(ctx, domainGUID)
 	if err != nil {
 		return "", err
 	}
@@ -432,7 +839,13 @@ func getDomain(appName string) (string, error) {
 	return domain, nil
 }
 
-func cf(updates chan string, args ...string) (string, error) {
+func cf(ctx context.Context, updates chan string, args ...string) (string, error) {
+	return cfWithEnv(ctx, updates, nil, args...)
+}
+
+// cfWithEnv runs the CF CLI with additional environment variables, which is
+// used to hand over credentials without exposing them on the command line
+func cfWithEnv(ctx context.Context, updates chan string, env []string, args ...string) (string, error) {
 	var (
 		buf bytes.Buffer
 		err error
@@ -441,7 +854,10 @@ func cf(updates chan string, args ...string) (string, error) {
 	read, write := io.Pipe()
 	go func() {
 		// TODO Check if cf binary is available
-		cmd := exec.Command("cf", args...)
+		cmd := exec.CommandContext(ctx, "cf", args...)
+		if len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
 
 		cmd.Stdout = write
 		cmd.Stderr = write
@@ -469,8 +885,8 @@ func cf(updates chan string, args ...string) (string, error) {
 	return buf.String(), err
 }
 
-func cfAppGUID(appName string) (string, error) {
-	result, err := cf(nil, "app", appName, "--guid")
+func cfAppGUID(ctx context.Context, appName string) (string, error) {
+	result, err := cf(ctx, nil, "app", appName, "--guid")
 	if err != nil {
 		return "", err
 	}
@@ -478,7 +894,7 @@ func cfAppGUID(appName string) (string, error) {
 	return strings.Trim(result, " \n"), nil
 }
 
-func cfCurlOrgsSpaceApps() ([]AppDetails, error) {
+func cfCurlOrgsSpaceApps(ctx context.Context) ([]AppDetails, error) {
 	var apps []AppDetails
 	var result string
 	var err error
@@ -486,7 +902,7 @@ func cfCurlOrgsSpaceApps() ([]AppDetails, error) {
 	nextURL := "/v2/apps"
 
 	for {
-		result, err = cf(nil, "curl", nextURL)
+		result, err = cf(ctx, nil, "curl", nextURL)
 
 		if err != nil {
 			return nil, err
@@ -510,8 +926,8 @@ func cfCurlOrgsSpaceApps() ([]AppDetails, error) {
 	return apps, nil
 }
 
-func cfCurlAppByGUID(appGUID string) (*AppDetails, error) {
-	result, err := cf(nil, "curl", fmt.Sprintf("/v2/apps/%s", appGUID))
+func cfCurlAppByGUID(ctx context.Context, appGUID string) (*AppDetails, error) {
+	result, err := cf(ctx, nil, "curl", fmt.Sprintf("/v2/apps/%s", appGUID))
 	if err != nil {
 		return nil, err
 	}
@@ -524,8 +940,8 @@ func cfCurlAppByGUID(appGUID string) (*AppDetails, error) {
 	return &app, nil
 }
 
-func cfCurlBuildpackByGUID(buildpackGUID string) (*BuildpackDetails, error) {
-	result, err := cf(nil, "curl", fmt.Sprintf("/v2/buildpacks/%s", buildpackGUID))
+func cfCurlBuildpackByGUID(ctx context.Context, buildpackGUID string) (*BuildpackDetails, error) {
+	result, err := cf(ctx, nil, "curl", fmt.Sprintf("/v2/buildpacks/%s", buildpackGUID))
 	if err != nil {
 		return nil, err
 	}
@@ -538,8 +954,22 @@ func cfCurlBuildpackByGUID(buildpackGUID string) (*BuildpackDetails, error) {
 	return &buildpack, nil
 }
 
-func cfCurlStackURL(stackURL string) (*StackDetails, error) {
-	result, err := cf(nil, "curl", stackURL)
+func cfCurlCurrentDroplet(ctx context.Context, appGUID string) (*DropletDetails, error) {
+	result, err := cf(ctx, nil, "curl", fmt.Sprintf("/v3/apps/%s/droplets/current", appGUID))
+	if err != nil {
+		return nil, err
+	}
+
+	var droplet DropletDetails
+	if err := json.Unmarshal([]byte(result), &droplet); err != nil {
+		return nil, err
+	}
+
+	return &droplet, nil
+}
+
+func cfCurlStackURL(ctx context.Context, stackURL string) (*StackDetails, error) {
+	result, err := cf(ctx, nil, "curl", stackURL)
 	if err != nil {
 		return nil, err
 	}
@@ -552,8 +982,8 @@ func cfCurlStackURL(stackURL string) (*StackDetails, error) {
 	return &stack, nil
 }
 
-func cfCurlRouteURL(routesURL string) (*RoutePage, error) {
-	result, err := cf(nil, "curl", routesURL)
+func cfCurlRouteURL(ctx context.Context, routesURL string) (*RoutePage, error) {
+	result, err := cf(ctx, nil, "curl", routesURL)
 	if err != nil {
 		return nil, err
 	}
@@ -566,8 +996,8 @@ func cfCurlRouteURL(routesURL string) (*RoutePage, error) {
 	return &route, nil
 }
 
-func cfCurlDomainByGUID(domainGUID string) (*DomainDetails, error) {
-	result, err := cf(nil, "curl", fmt.Sprintf("/v2/shared_domains/%s", domainGUID))
+func cfCurlDomainByGUID(ctx context.Context, domainGUID string) (*DomainDetails, error) {
+	result, err := cf(ctx, nil, "curl", fmt.Sprintf("/v2/shared_domains/%s", domainGUID))
 	if err != nil {
 		return nil, err
 	}
@@ -21,6 +21,7 @@
 package cf
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -61,3 +62,30 @@ func HumanReadableDuration(duration time.Duration) string {
 
 	return strings.Join(parts, " ")
 }
+
+// HumanReadableSize returns a human readable version of the size in bytes
+func HumanReadableSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// CleanupContext returns a new context for a cleanup, which is independent of
+// any other context so that the cleanup also runs after gonut was interrupted,
+// a timeout of zero means that there is no time limit
+func CleanupContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+
+	return context.WithCancel(context.Background())
+}
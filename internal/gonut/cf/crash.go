@@ -0,0 +1,87 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+)
+
+// crashPollInterval is the time to wait between two checks whether a crashed
+// app instance is back
+const crashPollInterval = 500 * time.Millisecond
+
+// verifyCrashRecovery crashes the first instance of the app using the crash
+// endpoint of the sample app and measures the time it takes until the
+// instance is restarted and routable again
+func verifyCrashRecovery(ctx context.Context, appName string, appRoute string, threshold time.Duration) (time.Duration, error) {
+	caption := fmt.Sprintf("application %s did not recover from a crash", appName)
+
+	appGUID, err := cfAppGUID(ctx, appName)
+	if err != nil {
+		return 0, err
+	}
+
+	// Requests are pinned to the first instance, so that the crash and the
+	// recovery checks target the same instance in case there are multiple
+	header := http.Header{}
+	header.Set("X-Cf-App-Instance", fmt.Sprintf("%s:%d", appGUID, 0))
+
+	crashStart := time.Now()
+
+	// The request is expected to fail, because the app exits right away
+	getAppStatusCodeWithHeader(ctx, fmt.Sprintf("%s/crash", appRoute), header)
+
+	restarted := false
+	for {
+		if !restarted {
+			if stats, err := cfCurlAppStats(ctx, appGUID); err == nil {
+				instance, ok := stats["0"]
+				restarted = ok &&
+					instance.State == "RUNNING" &&
+					time.Duration(instance.Stats.Uptime)*time.Second <= time.Since(crashStart)
+			}
+		}
+
+		if restarted {
+			if statusCode, err := getAppStatusCodeWithHeader(ctx, appRoute, header); err == nil && statusCode == http.StatusOK {
+				return time.Since(crashStart), nil
+			}
+		}
+
+		if time.Since(crashStart) > threshold {
+			return time.Since(crashStart), nok.Errorf(caption,
+				"The crashed app instance was not running and routable again within the threshold of %s.", threshold,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return time.Since(crashStart), ctx.Err()
+
+		case <-time.After(crashPollInterval):
+		}
+	}
+}
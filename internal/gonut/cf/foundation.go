@@ -0,0 +1,161 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Foundation describes a named Cloud Foundry environment including everything
+// that is required to log in and to target an org and space
+type Foundation struct {
+	Name              string `yaml:"name"`
+	API               string `yaml:"api"`
+	Username          string `yaml:"username"`
+	Password          string `yaml:"password"`
+	Org               string `yaml:"org"`
+	Space             string `yaml:"space"`
+	SkipSSLValidation bool   `yaml:"skip-ssl-validation"`
+}
+
+// FoundationsConfig is the Go struct for the gonut foundations configuration YAML
+type FoundationsConfig struct {
+	Foundations []Foundation `yaml:"foundations"`
+}
+
+// LoadFoundations reads the foundation profiles from the given YAML file,
+// environment variable references in usernames and passwords are expanded
+func LoadFoundations(path string) ([]Foundation, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nok.Errorf(
+			"failed to load foundations configuration",
+			"An error occurred while trying to read %s: %v", path, err,
+		)
+	}
+
+	var config FoundationsConfig
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, nok.Errorf(
+			"failed to load foundations configuration",
+			"An error occurred while trying to parse %s: %v", path, err,
+		)
+	}
+
+	for i := range config.Foundations {
+		config.Foundations[i].Username = os.ExpandEnv(config.Foundations[i].Username)
+		config.Foundations[i].Password = os.ExpandEnv(config.Foundations[i].Password)
+	}
+
+	return config.Foundations, nil
+}
+
+// LookUpFoundations returns the foundations with the given names in the order
+// of the provided names
+func LookUpFoundations(foundations []Foundation, names []string) ([]Foundation, error) {
+	result := make([]Foundation, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+
+		var found *Foundation
+		for i := range foundations {
+			if foundations[i].Name == name {
+				found = &foundations[i]
+				break
+			}
+		}
+
+		if found == nil {
+			return nil, nok.Errorf(
+				fmt.Sprintf("unknown foundation %s", name),
+				"There is no foundation named %s in the foundations configuration.", name,
+			)
+		}
+
+		result = append(result, *found)
+	}
+
+	return result, nil
+}
+
+// WithFoundation logs into the given foundation using a dedicated temporary
+// Cloud Foundry CLI home directory and runs the provided function while it is
+// set as CF_HOME. The previous CF_HOME setting is restored afterwards.
+func WithFoundation(ctx context.Context, foundation Foundation, f func() error) error {
+	return runWithTempDir(func(path string) error {
+		previous, wasSet := os.LookupEnv("CF_HOME")
+		defer func() {
+			if wasSet {
+				os.Setenv("CF_HOME", previous)
+			} else {
+				os.Unsetenv("CF_HOME")
+			}
+		}()
+
+		if err := os.Setenv("CF_HOME", path); err != nil {
+			return err
+		}
+
+		if err := loginToFoundation(ctx, foundation); err != nil {
+			return err
+		}
+
+		return f()
+	})
+}
+
+func loginToFoundation(ctx context.Context, foundation Foundation) error {
+	caption := fmt.Sprintf("failed to log into foundation %s", foundation.Name)
+
+	apiArgs := []string{"api", foundation.API}
+	if foundation.SkipSSLValidation {
+		apiArgs = append(apiArgs, "--skip-ssl-validation")
+	}
+
+	if output, err := cf(ctx, nil, apiArgs...); err != nil {
+		return nok.Errorf(caption, output)
+	}
+
+	// Hand the credentials over using the environment, so that they do not show
+	// up in the process list, and do not report the output of the auth call
+	credentials := []string{
+		"CF_USERNAME=" + foundation.Username,
+		"CF_PASSWORD=" + foundation.Password,
+	}
+
+	if _, err := cfWithEnv(ctx, nil, credentials, "auth"); err != nil {
+		return nok.Errorf(caption, "Authentication of user %s against %s failed.", foundation.Username, foundation.API)
+	}
+
+	if output, err := cf(ctx, nil, "target", "-o", foundation.Org, "-s", foundation.Space); err != nil {
+		return nok.Errorf(caption, output)
+	}
+
+	return nil
+}
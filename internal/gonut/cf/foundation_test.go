@@ -0,0 +1,74 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/gonut/internal/gonut/cf"
+)
+
+var _ = Describe("Foundations configuration", func() {
+	Context("Loading foundation profiles", func() {
+		BeforeEach(func() {
+			os.Setenv("GONUT_TEST_EU_PROD_PASSWORD", "from-env")
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("GONUT_TEST_EU_PROD_PASSWORD")
+		})
+
+		It("should parse the foundations configuration file", func() {
+			foundations, err := LoadFoundations("../../../assets/test/foundations/foundations.yml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(foundations)).To(BeEquivalentTo(2))
+
+			Expect(foundations[0].Name).To(BeEquivalentTo("eu-prod"))
+			Expect(foundations[0].Password).To(BeEquivalentTo("from-env"))
+			Expect(foundations[0].SkipSSLValidation).To(BeFalse())
+
+			Expect(foundations[1].Name).To(BeEquivalentTo("us-prod"))
+			Expect(foundations[1].Space).To(BeEquivalentTo("smoke-tests"))
+			Expect(foundations[1].SkipSSLValidation).To(BeTrue())
+		})
+
+		It("should look up foundations by name in the requested order", func() {
+			foundations, err := LoadFoundations("../../../assets/test/foundations/foundations.yml")
+			Expect(err).ToNot(HaveOccurred())
+
+			selected, err := LookUpFoundations(foundations, []string{"us-prod", "eu-prod"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected[0].Name).To(BeEquivalentTo("us-prod"))
+			Expect(selected[1].Name).To(BeEquivalentTo("eu-prod"))
+		})
+
+		It("should fail for unknown foundation names", func() {
+			foundations, err := LoadFoundations("../../../assets/test/foundations/foundations.yml")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = LookUpFoundations(foundations, []string{"ap-prod"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
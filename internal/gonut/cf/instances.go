@@ -0,0 +1,169 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+)
+
+// instancesPollInterval is the time to wait between two app instance checks
+const instancesPollInterval = 2 * time.Second
+
+// defaultInstancesTimeout is the time all app instances have to reach the
+// running state in, if there is no start timeout configured
+const defaultInstancesTimeout = 3 * time.Minute
+
+// InstanceReport contains the details of a single app instance
+type InstanceReport struct {
+	Index      int
+	State      string
+	Cell       string
+	Since      time.Time
+	StatusCode int
+}
+
+// waitForRunningInstances polls the app instance stats until the expected
+// number of instances is running and returns the details of each instance
+func waitForRunningInstances(ctx context.Context, appName string, expected int, timeout time.Duration) ([]InstanceReport, error) {
+	caption := fmt.Sprintf("not all instances of application %s are running", appName)
+
+	if timeout <= 0 {
+		timeout = defaultInstancesTimeout
+	}
+
+	appGUID, err := cfAppGUID(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		stats, err := cfCurlAppStats(ctx, appGUID)
+		if err != nil {
+			return nil, err
+		}
+
+		instances := toInstanceReports(stats)
+
+		running := 0
+		for _, instance := range instances {
+			if instance.State == "RUNNING" {
+				running++
+			}
+		}
+
+		if running >= expected {
+			return instances, nil
+		}
+
+		if time.Now().After(deadline) {
+			return instances, nok.Errorf(caption,
+				"Only %d out of %d instances reached the running state within %s.", running, expected, timeout,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return instances, ctx.Err()
+
+		case <-time.After(instancesPollInterval):
+		}
+	}
+}
+
+// probeAppInstances sends a request to each app instance through the router
+// and records the returned statuscode in the instance report
+func probeAppInstances(ctx context.Context, appName string, appRoute string, instances []InstanceReport) error {
+	appGUID, err := cfAppGUID(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	for i := range instances {
+		header := http.Header{}
+		header.Set("X-Cf-App-Instance", fmt.Sprintf("%s:%d", appGUID, instances[i].Index))
+
+		statusCode, err := getAppStatusCodeWithHeader(ctx, appRoute, header)
+		if err != nil {
+			return nok.Errorf(
+				fmt.Sprintf("unable to ping instance %d of application %s with route %s", instances[i].Index, appName, appRoute),
+				err.Error(),
+			)
+		}
+
+		instances[i].StatusCode = statusCode
+		if statusCode != http.StatusOK {
+			return nok.Errorf(
+				fmt.Sprintf("instance %d of application %s returned a non-ok statuscode %d", instances[i].Index, appName, statusCode),
+				"The application instance did not return the statuscode 200, it is running on cell %s.", instances[i].Cell,
+			)
+		}
+	}
+
+	return nil
+}
+
+func toInstanceReports(stats AppStats) []InstanceReport {
+	now := time.Now()
+
+	result := make([]InstanceReport, 0, len(stats))
+	for key, instance := range stats {
+		index, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, InstanceReport{
+			Index: index,
+			State: instance.State,
+			Cell:  instance.Stats.Host,
+			Since: now.Add(-time.Duration(instance.Stats.Uptime) * time.Second),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Index < result[j].Index
+	})
+
+	return result
+}
+
+func cfCurlAppStats(ctx context.Context, appGUID string) (AppStats, error) {
+	result, err := cf(ctx, nil, "curl", fmt.Sprintf("/v2/apps/%s/stats", appGUID))
+	if err != nil {
+		return nil, err
+	}
+
+	var stats AppStats
+	if err := json.Unmarshal([]byte(result), &stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,237 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gonvenience/wait"
+	"github.com/homeport/gonut/internal/gonut/nok"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Supported lifecycle operations that can run after a successful push
+const (
+	Restart = "restart"
+	Restage = "restage"
+	Scale   = "scale"
+)
+
+// LifecycleReport encapsules details of a lifecycle operation, for example a
+// restage, that runs on an already pushed app
+type LifecycleReport struct {
+	Operation string
+
+	InitStart     time.Time
+	StoppingStart time.Time
+	StagingStart  time.Time
+	StartingStart time.Time
+	End           time.Time
+}
+
+// StoppingTime is the time it takes to stop the app
+func (report LifecycleReport) StoppingTime() time.Duration {
+	return phaseDuration(report.StoppingStart, report.StagingStart, report.StartingStart, report.End)
+}
+
+// StagingTime is the time it takes to stage the app again
+func (report LifecycleReport) StagingTime() time.Duration {
+	return phaseDuration(report.StagingStart, report.StartingStart, report.End)
+}
+
+// StartingTime is the time it takes to start the app again
+func (report LifecycleReport) StartingTime() time.Duration {
+	return phaseDuration(report.StartingStart, report.End)
+}
+
+// ElapsedTime is the overall elapsed time of the lifecycle operation
+func (report LifecycleReport) ElapsedTime() time.Duration {
+	return report.End.Sub(report.InitStart)
+}
+
+// ParseUpdate parses a line from the CF CLI restart, restage, scale, or rolling
+// push output
+func (report *LifecycleReport) ParseUpdate(text string) string {
+	switch {
+	case strings.HasPrefix(text, "Stopping app"):
+		report.StoppingStart = time.Now()
+		return "Stopping"
+
+	case strings.HasPrefix(text, "Staging app") || strings.HasPrefix(text, "Staging..."):
+		report.StagingStart = time.Now()
+		return "Staging"
+
+	case strings.HasPrefix(text, "Waiting for app to start...") || strings.HasPrefix(text, "Waiting for app to deploy...") || strings.HasPrefix(text, "Starting app"):
+		report.StartingStart = time.Now()
+		return "Starting"
+	}
+
+	return ""
+}
+
+// Export creates a less technical representation of the report
+func (report *LifecycleReport) Export() yaml.MapSlice {
+	result := yaml.MapSlice{
+		yaml.MapItem{Key: "elapsed", Value: report.ElapsedTime()},
+	}
+
+	for _, item := range []yaml.MapItem{
+		{Key: "stopping", Value: report.StoppingTime()},
+		{Key: "staging", Value: report.StagingTime()},
+		{Key: "starting", Value: report.StartingTime()},
+	} {
+		if item.Value.(time.Duration) > 0 {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// ExportTable creates a less technical representation of the report in form of
+// a two-dimensional array
+func (report *LifecycleReport) ExportTable() [][]string {
+	return exportTable(report.Export())
+}
+
+// phaseDuration returns the time between the start and the first of the given
+// later timestamps that is set, or zero if the phase did not happen
+func phaseDuration(start time.Time, ends ...time.Time) time.Duration {
+	if start.IsZero() {
+		return time.Duration(0)
+	}
+
+	for _, end := range ends {
+		if !end.IsZero() {
+			return end.Sub(start)
+		}
+	}
+
+	return time.Duration(0)
+}
+
+// runLifecycleOperation runs the given lifecycle operation on the pushed app
+// and tracks the time of each phase of it
+func runLifecycleOperation(ctx context.Context, spinner *wait.ProgressIndicator, phases *phaseTracker, caption string, appName string, operation string, instances int, startTimeout time.Duration) (LifecycleReport, error) {
+	report := LifecycleReport{Operation: operation}
+
+	var args []string
+	switch operation {
+	case Restart:
+		args = []string{"restart", appName}
+
+	case Restage:
+		args = []string{"restage", appName}
+
+	case Scale:
+		if instances < 1 {
+			instances = 1
+		}
+
+		args = []string{"scale", appName, "-i", strconv.Itoa(instances + 1)}
+
+	default:
+		return report, fmt.Errorf("unsupported lifecycle operation: %s", operation)
+	}
+
+	output, err := runTrackedCommand(ctx, spinner, phases, caption, strings.Title(operation), &report, args...)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		return report, nok.Errorf(
+			fmt.Sprintf("failed to %s application %s", operation, appName),
+			output,
+		)
+	}
+
+	if operation == Scale {
+		return report, waitForScaledInstances(ctx, spinner, phases, caption, appName, instances, startTimeout, &report)
+	}
+
+	return report, nil
+}
+
+// waitForScaledInstances waits until the additional instance of a scale
+// operation is running, which is tracked as the starting phase, and scales the
+// app back to its original number of instances afterwards so that subsequent
+// checks run against the app as it was pushed
+func waitForScaledInstances(ctx context.Context, spinner *wait.ProgressIndicator, phases *phaseTracker, caption string, appName string, instances int, startTimeout time.Duration, report *LifecycleReport) error {
+	// The CF CLI returns as soon as the API accepted the new instance count
+	report.StartingStart = report.End
+	phases.enter("Starting")
+	spinner.SetText("*%s*, DimGray{%s} - waiting for %d running instances", caption, "Starting", instances+1)
+
+	_, err := waitForRunningInstances(ctx, appName, instances+1, startTimeout)
+	report.End = time.Now()
+	phases.enter("Verifying")
+	if err != nil {
+		return err
+	}
+
+	if output, err := cf(ctx, nil, "scale", appName, "-i", strconv.Itoa(instances)); err != nil {
+		return operationError(ctx, fmt.Sprintf("failed to scale application %s back to %d instances", appName, instances), output)
+	}
+
+	return nil
+}
+
+// runTrackedCommand runs the CF CLI with the given arguments, shows its output
+// in the spinner, and tracks the phases of it in the provided report
+func runTrackedCommand(ctx context.Context, spinner *wait.ProgressIndicator, phases *phaseTracker, caption string, step string, report *LifecycleReport, args ...string) (string, error) {
+	phases.enter(step)
+
+	updates := make(chan string)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for update := range updates {
+			if text := strings.Trim(update, " "); len(text) > 0 {
+				if result := report.ParseUpdate(text); result != "" {
+					step = result
+					phases.enter(step)
+				}
+
+				spinner.SetText("*%s*, DimGray{%s} - %s",
+					caption,
+					step,
+					text,
+				)
+			}
+		}
+	}()
+
+	report.InitStart = time.Now()
+	output, err := cf(ctx, updates, args...)
+	close(updates)
+	<-done
+
+	report.End = time.Now()
+	phases.enter("Verifying")
+
+	return output, err
+}
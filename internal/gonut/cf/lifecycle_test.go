@@ -0,0 +1,90 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/gonut/internal/gonut/cf"
+)
+
+func createMockLifecycleReport(operation string, path string) *LifecycleReport {
+	report := &LifecycleReport{
+		Operation: operation,
+		InitStart: time.Now(),
+	}
+
+	linefeeder(path, func(text string) {
+		report.ParseUpdate(text)
+	})
+
+	report.End = time.Now()
+
+	return report
+}
+
+var _ = Describe("Cloud Foundry lifecycle operation report", func() {
+	var (
+		unset = time.Time{}
+	)
+
+	Context("Parse Cloud Foundry lifecycle operation output", func() {
+		It("should parse restage logs", func() {
+			report := createMockLifecycleReport(Restage, "../../../assets/test/cf-lifecycle/restage.log")
+
+			Expect(report.StoppingStart).To(BeEquivalentTo(unset))
+			Expect(report.StagingStart).ToNot(BeEquivalentTo(unset))
+			Expect(report.StartingStart).ToNot(BeEquivalentTo(unset))
+		})
+
+		It("should parse restart logs", func() {
+			report := createMockLifecycleReport(Restart, "../../../assets/test/cf-lifecycle/restart.log")
+
+			Expect(report.StoppingStart).ToNot(BeEquivalentTo(unset))
+			Expect(report.StagingStart).To(BeEquivalentTo(unset))
+			Expect(report.StartingStart).ToNot(BeEquivalentTo(unset))
+		})
+	})
+
+	Context("Export lifecycle operation reports", func() {
+		It("should only list the phases that took place", func() {
+			start := time.Now()
+			report := &LifecycleReport{
+				Operation:     Restart,
+				InitStart:     start,
+				StoppingStart: start.Add(1 * time.Second),
+				StartingStart: start.Add(3 * time.Second),
+				End:           start.Add(8 * time.Second),
+			}
+
+			export := report.Export()
+			Expect(len(export)).To(BeEquivalentTo(3))
+			Expect(export[0].Value).To(BeEquivalentTo(8 * time.Second))
+			Expect(export[1].Key).To(BeEquivalentTo("stopping"))
+			Expect(export[1].Value).To(BeEquivalentTo(2 * time.Second))
+			Expect(export[2].Key).To(BeEquivalentTo("starting"))
+			Expect(export[2].Value).To(BeEquivalentTo(5 * time.Second))
+		})
+	})
+})
@@ -0,0 +1,242 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// loadRequestTimeout is the maximum time a single request of the load probe
+// may take before it counts as an error
+const loadRequestTimeout = 10 * time.Second
+
+// maxLoadRate is the highest supported request rate of the load probe
+const maxLoadRate = 1000
+
+// LoadSettings defines the duration and the request rate of a load probe
+type LoadSettings struct {
+	Duration time.Duration
+	Rate     int
+}
+
+// LoadReport contains the results of a load probe against the app route
+type LoadReport struct {
+	Settings LoadSettings
+
+	Requests int
+	Errors   int
+	Elapsed  time.Duration
+
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// ParseLoadSettings parses a load setting in the form <duration>@<rate>rps,
+// for example 30s@50rps
+func ParseLoadSettings(setting string) (LoadSettings, error) {
+	var result LoadSettings
+
+	parts := strings.Split(setting, "@")
+	if len(parts) != 2 || !strings.HasSuffix(parts[1], "rps") {
+		return result, fmt.Errorf("unsupported load setting %s, expected <duration>@<rate>rps, for example 30s@50rps", setting)
+	}
+
+	duration, err := time.ParseDuration(parts[0])
+	if err != nil || duration <= 0 {
+		return result, fmt.Errorf("unsupported load duration %s in load setting %s", parts[0], setting)
+	}
+
+	rate, err := strconv.Atoi(strings.TrimSuffix(parts[1], "rps"))
+	if err != nil || rate <= 0 || rate > maxLoadRate {
+		return result, fmt.Errorf("unsupported load rate %s in load setting %s", parts[1], setting)
+	}
+
+	result.Duration = duration
+	result.Rate = rate
+	return result, nil
+}
+
+// IsEmpty returns true if no load probe is configured
+func (settings LoadSettings) IsEmpty() bool {
+	return settings.Duration == 0 || settings.Rate == 0
+}
+
+// Throughput is the number of successful requests per second
+func (report LoadReport) Throughput() float64 {
+	if report.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(report.Requests-report.Errors) / report.Elapsed.Seconds()
+}
+
+// Export creates a less technical representation of the report
+func (report *LoadReport) Export() yaml.MapSlice {
+	return yaml.MapSlice{
+		yaml.MapItem{Key: "duration", Value: report.Settings.Duration},
+		yaml.MapItem{Key: "target rate", Value: fmt.Sprintf("%d rps", report.Settings.Rate)},
+		yaml.MapItem{Key: "requests", Value: report.Requests},
+		yaml.MapItem{Key: "errors", Value: report.Errors},
+		yaml.MapItem{Key: "throughput", Value: fmt.Sprintf("%.1f rps", report.Throughput())},
+		yaml.MapItem{Key: "p50", Value: report.P50},
+		yaml.MapItem{Key: "p90", Value: report.P90},
+		yaml.MapItem{Key: "p99", Value: report.P99},
+		yaml.MapItem{Key: "max", Value: report.Max},
+	}
+}
+
+// ExportTable creates a less technical representation of the report in form of
+// a two-dimensional array
+func (report *LoadReport) ExportTable() [][]string {
+	return exportTable(report.Export())
+}
+
+// NewLoadReport creates a load report from the latencies of the successful
+// requests and the number of failed requests
+func NewLoadReport(settings LoadSettings, elapsed time.Duration, latencies []time.Duration, errors int) *LoadReport {
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	report := &LoadReport{
+		Settings: settings,
+		Requests: len(latencies) + errors,
+		Errors:   errors,
+		Elapsed:  elapsed,
+		P50:      percentile(sorted, 50),
+		P90:      percentile(sorted, 90),
+		P99:      percentile(sorted, 99),
+	}
+
+	if len(sorted) > 0 {
+		report.Max = sorted[len(sorted)-1]
+	}
+
+	return report
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return time.Duration(0)
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// runLoadProbe sends requests to the app route at a steady rate for the
+// configured duration and measures the latency of each request
+func runLoadProbe(ctx context.Context, appName string, appRoute string, settings LoadSettings) (*LoadReport, error) {
+	client := &http.Client{
+		Timeout: loadRequestTimeout,
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			MaxIdleConnsPerHost: settings.Rate,
+		},
+	}
+
+	var (
+		latencies []time.Duration
+		errors    int
+		lock      sync.Mutex
+		wg        sync.WaitGroup
+	)
+
+	request := func() {
+		defer wg.Done()
+
+		start := time.Now()
+		ok := false
+
+		req, err := http.NewRequest(http.MethodGet, appRoute, nil)
+		if err == nil {
+			if resp, err := client.Do(req.WithContext(ctx)); err == nil {
+				io.Copy(ioutil.Discard, resp.Body)
+				resp.Body.Close()
+				ok = resp.StatusCode == http.StatusOK
+			}
+		}
+
+		latency := time.Since(start)
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		if ok {
+			latencies = append(latencies, latency)
+		} else {
+			errors++
+		}
+	}
+
+	ticker := time.NewTicker(time.Second / time.Duration(settings.Rate))
+	defer ticker.Stop()
+
+	start := time.Now()
+	deadline := time.After(settings.Duration)
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+
+		case <-deadline:
+			break loop
+
+		case <-ticker.C:
+			wg.Add(1)
+			go request()
+		}
+	}
+
+	wg.Wait()
+	report := NewLoadReport(settings, time.Since(start), latencies, errors)
+
+	if report.Requests > 0 && report.Errors == report.Requests {
+		return report, nok.Errorf(
+			fmt.Sprintf("application %s did not answer any request of the load probe", appName),
+			"All %d requests sent to %s failed.", report.Requests, appRoute,
+		)
+	}
+
+	return report, nil
+}
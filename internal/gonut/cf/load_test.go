@@ -0,0 +1,72 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/gonut/internal/gonut/cf"
+)
+
+var _ = Describe("HTTP load probe", func() {
+	Context("Parse load settings", func() {
+		It("should parse duration and rate", func() {
+			settings, err := ParseLoadSettings("30s@50rps")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(settings).To(BeEquivalentTo(LoadSettings{Duration: 30 * time.Second, Rate: 50}))
+			Expect(settings.IsEmpty()).To(BeFalse())
+		})
+
+		It("should reject unsupported load settings", func() {
+			for _, setting := range []string{"30s", "30s@50", "foo@50rps", "30s@0rps", "30s@xrps", "-1s@50rps", "30s@5000rps"} {
+				_, err := ParseLoadSettings(setting)
+				Expect(err).To(HaveOccurred(), setting)
+			}
+		})
+	})
+
+	Context("Create load reports", func() {
+		It("should calculate percentiles, errors, and throughput", func() {
+			latencies := []time.Duration{}
+			for i := 100; i >= 1; i-- {
+				latencies = append(latencies, time.Duration(i)*time.Millisecond)
+			}
+
+			report := NewLoadReport(LoadSettings{Duration: 10 * time.Second, Rate: 11}, 10*time.Second, latencies, 10)
+			Expect(report.Requests).To(BeEquivalentTo(110))
+			Expect(report.Errors).To(BeEquivalentTo(10))
+			Expect(report.P50).To(BeEquivalentTo(50 * time.Millisecond))
+			Expect(report.P90).To(BeEquivalentTo(90 * time.Millisecond))
+			Expect(report.P99).To(BeEquivalentTo(99 * time.Millisecond))
+			Expect(report.Max).To(BeEquivalentTo(100 * time.Millisecond))
+			Expect(report.Throughput()).To(BeNumerically("~", 10.0))
+		})
+
+		It("should handle a load probe without successful requests", func() {
+			report := NewLoadReport(LoadSettings{Duration: time.Second, Rate: 5}, time.Second, nil, 5)
+			Expect(report.P99).To(BeEquivalentTo(time.Duration(0)))
+			Expect(report.Throughput()).To(BeEquivalentTo(0))
+		})
+	})
+})
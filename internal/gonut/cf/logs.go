@@ -0,0 +1,152 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+)
+
+// logStreamTimeout is the maximum time to wait for the log stream to connect
+// and for the marker to show up in the log stream
+const logStreamTimeout = 30 * time.Second
+
+// logStreamConnectGrace is the time to wait after the log stream printed its
+// first line, so that the stream is fully connected before the marker is sent
+const logStreamConnectGrace = 2 * time.Second
+
+// LogReport contains the outcome of the log streaming check
+type LogReport struct {
+	Marker  string
+	Latency time.Duration
+	Lost    bool
+}
+
+// verifyLogStreaming streams the app logs using cf logs, makes the app print a
+// unique marker line using the log endpoint of the sample app, and measures the
+// time until the marker shows up in the log stream
+func verifyLogStreaming(ctx context.Context, appName string, appRoute string) (*LogReport, error) {
+	report := &LogReport{
+		Marker: fmt.Sprintf("gonut-log-marker-%d", time.Now().UnixNano()),
+	}
+
+	streamCtx, stopStream := context.WithCancel(ctx)
+
+	updates := make(chan string)
+	connected := make(chan struct{})
+	found := make(chan time.Time, 1)
+	go func() {
+		first := true
+		for line := range updates {
+			if first {
+				close(connected)
+				first = false
+			}
+
+			if isAppOutputLine(line, report.Marker) {
+				select {
+				case found <- time.Now():
+				default:
+				}
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cf(streamCtx, updates, "logs", appName)
+		close(updates)
+	}()
+
+	defer func() {
+		stopStream()
+		<-done
+	}()
+
+	select {
+	case <-connected:
+		time.Sleep(logStreamConnectGrace)
+
+	case <-done:
+		return report, operationError(ctx,
+			fmt.Sprintf("failed to stream logs of application %s", appName),
+			"The cf logs command ended before the log stream was connected.",
+		)
+
+	case <-time.After(logStreamTimeout):
+		return report, nok.Errorf(
+			fmt.Sprintf("failed to stream logs of application %s", appName),
+			"The log stream did not connect within %s.", logStreamTimeout,
+		)
+
+	case <-ctx.Done():
+		return report, ctx.Err()
+	}
+
+	start := time.Now()
+	statusCode, err := getAppStatusCode(ctx, fmt.Sprintf("%s/log?marker=%s", appRoute, report.Marker))
+	if err != nil {
+		return report, operationError(ctx,
+			fmt.Sprintf("unable to request the log endpoint of application %s", appName),
+			err.Error(),
+		)
+	}
+
+	if statusCode != http.StatusOK {
+		return report, nok.Errorf(
+			fmt.Sprintf("unable to request the log endpoint of application %s", appName),
+			"The log endpoint returned the statuscode %d.", statusCode,
+		)
+	}
+
+	select {
+	case end := <-found:
+		report.Latency = end.Sub(start)
+
+	case <-time.After(logStreamTimeout):
+		report.Lost = true
+
+	case <-ctx.Done():
+		return report, ctx.Err()
+	}
+
+	return report, nil
+}
+
+// isAppOutputLine returns true if the log line is a line the app printed to
+// standard out and it contains the marker, the marker is also part of the
+// request URL, which shows up in the access log line of the router
+func isAppOutputLine(line string, marker string) bool {
+	fields := strings.Fields(line)
+	for i := 0; i+2 < len(fields); i++ {
+		if strings.HasPrefix(fields[i], "[APP/") && fields[i+1] == "OUT" {
+			return fields[i+2] == marker
+		}
+	}
+
+	return false
+}
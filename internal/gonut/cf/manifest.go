@@ -0,0 +1,236 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ManifestOverrides contains app settings that replace the respective settings
+// of the sample app manifest, empty values leave the manifest untouched
+type ManifestOverrides struct {
+	Memory          string            `yaml:"memory"`
+	DiskQuota       string            `yaml:"disk_quota"`
+	Instances       int               `yaml:"instances"`
+	Stack           string            `yaml:"stack"`
+	Buildpack       string            `yaml:"buildpack"`
+	HealthCheckType string            `yaml:"health-check-type"`
+	Env             map[string]string `yaml:"env"`
+}
+
+// LoadManifestOverrides reads manifest overrides from the given YAML file
+func LoadManifestOverrides(path string) (ManifestOverrides, error) {
+	var overrides ManifestOverrides
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return overrides, nok.Errorf(
+			"failed to load manifest overrides",
+			"An error occurred while trying to read %s: %v", path, err,
+		)
+	}
+
+	if err := yaml.UnmarshalStrict(data, &overrides); err != nil {
+		return overrides, nok.Errorf(
+			"failed to load manifest overrides",
+			"An error occurred while trying to parse %s: %v", path, err,
+		)
+	}
+
+	return overrides, nil
+}
+
+// Merge returns a copy of the overrides where all settings that are set in the
+// other overrides take precedence
+func (o ManifestOverrides) Merge(other ManifestOverrides) ManifestOverrides {
+	result := o
+
+	if len(other.Memory) > 0 {
+		result.Memory = other.Memory
+	}
+
+	if len(other.DiskQuota) > 0 {
+		result.DiskQuota = other.DiskQuota
+	}
+
+	if other.Instances > 0 {
+		result.Instances = other.Instances
+	}
+
+	if len(other.Stack) > 0 {
+		result.Stack = other.Stack
+	}
+
+	if len(other.Buildpack) > 0 {
+		result.Buildpack = other.Buildpack
+	}
+
+	if len(other.HealthCheckType) > 0 {
+		result.HealthCheckType = other.HealthCheckType
+	}
+
+	if len(other.Env) > 0 {
+		result.Env = map[string]string{}
+		for key, value := range o.Env {
+			result.Env[key] = value
+		}
+
+		for key, value := range other.Env {
+			result.Env[key] = value
+		}
+	}
+
+	return result
+}
+
+// IsEmpty returns true if none of the override settings is set
+func (o ManifestOverrides) IsEmpty() bool {
+	return len(o.Memory) == 0 &&
+		len(o.DiskQuota) == 0 &&
+		o.Instances == 0 &&
+		len(o.Stack) == 0 &&
+		len(o.Buildpack) == 0 &&
+		len(o.HealthCheckType) == 0 &&
+		len(o.Env) == 0
+}
+
+// ApplyManifestOverrides rewrites the first application of the manifest at the
+// given path using the provided overrides. If there is no manifest file, a new
+// one is created for the app with the given name.
+func ApplyManifestOverrides(path string, appName string, overrides ManifestOverrides) error {
+	manifest := yaml.MapSlice{}
+
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return err
+		}
+
+	case os.IsNotExist(err):
+		manifest = yaml.MapSlice{
+			yaml.MapItem{Key: "applications", Value: []interface{}{
+				yaml.MapSlice{yaml.MapItem{Key: "name", Value: appName}},
+			}},
+		}
+
+	default:
+		return err
+	}
+
+	applications, ok := lookUpValue(manifest, "applications").([]interface{})
+	if !ok || len(applications) == 0 {
+		return fmt.Errorf("manifest %s does not contain any application", path)
+	}
+
+	app, ok := applications[0].(yaml.MapSlice)
+	if !ok {
+		return fmt.Errorf("manifest %s contains an unsupported application definition", path)
+	}
+
+	if len(overrides.Memory) > 0 {
+		app = setValue(app, "memory", overrides.Memory)
+	}
+
+	if len(overrides.DiskQuota) > 0 {
+		app = setValue(app, "disk_quota", overrides.DiskQuota)
+	}
+
+	if overrides.Instances > 0 {
+		app = setValue(app, "instances", overrides.Instances)
+	}
+
+	if len(overrides.Stack) > 0 {
+		app = setValue(app, "stack", overrides.Stack)
+	}
+
+	if len(overrides.Buildpack) > 0 {
+		app = removeValue(app, "buildpack")
+		app = setValue(app, "buildpacks", []interface{}{overrides.Buildpack})
+	}
+
+	if len(overrides.HealthCheckType) > 0 {
+		app = setValue(app, "health-check-type", overrides.HealthCheckType)
+	}
+
+	if len(overrides.Env) > 0 {
+		env, _ := lookUpValue(app, "env").(yaml.MapSlice)
+
+		keys := make([]string, 0, len(overrides.Env))
+		for key := range overrides.Env {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+		for _, key := range keys {
+			env = setValue(env, key, overrides.Env[key])
+		}
+
+		app = setValue(app, "env", env)
+	}
+
+	applications[0] = app
+
+	out, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, out, os.FileMode(0644))
+}
+
+func lookUpValue(mapslice yaml.MapSlice, key string) interface{} {
+	for _, item := range mapslice {
+		if item.Key == key {
+			return item.Value
+		}
+	}
+
+	return nil
+}
+
+func setValue(mapslice yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for i := range mapslice {
+		if mapslice[i].Key == key {
+			mapslice[i].Value = value
+			return mapslice
+		}
+	}
+
+	return append(mapslice, yaml.MapItem{Key: key, Value: value})
+}
+
+func removeValue(mapslice yaml.MapSlice, key string) yaml.MapSlice {
+	result := yaml.MapSlice{}
+	for _, item := range mapslice {
+		if item.Key != key {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,161 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/gonut/internal/gonut/cf"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type testManifest struct {
+	Applications []struct {
+		Name       string            `yaml:"name"`
+		Memory     string            `yaml:"memory"`
+		DiskQuota  string            `yaml:"disk_quota"`
+		Instances  int               `yaml:"instances"`
+		Stack      string            `yaml:"stack"`
+		Buildpack  string            `yaml:"buildpack"`
+		Buildpacks []string          `yaml:"buildpacks"`
+		Env        map[string]string `yaml:"env"`
+	} `yaml:"applications"`
+}
+
+func readTestManifest(path string) testManifest {
+	data, err := ioutil.ReadFile(path)
+	Expect(err).ToNot(HaveOccurred())
+
+	var manifest testManifest
+	Expect(yaml.Unmarshal(data, &manifest)).ToNot(HaveOccurred())
+	Expect(len(manifest.Applications)).To(BeEquivalentTo(1))
+
+	return manifest
+}
+
+var _ = Describe("Sample app manifest overrides", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "gonut-manifest-test")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Context("Rewriting an existing manifest", func() {
+		It("should replace the settings and keep the existing ones", func() {
+			data, err := ioutil.ReadFile("../../../assets/sample-apps/python/manifest.yml")
+			Expect(err).ToNot(HaveOccurred())
+
+			path := filepath.Join(tmpDir, "manifest.yml")
+			Expect(ioutil.WriteFile(path, data, 0644)).ToNot(HaveOccurred())
+
+			Expect(ApplyManifestOverrides(path, "the-app-name", ManifestOverrides{
+				Memory:    "256M",
+				Instances: 2,
+				Stack:     "cflinuxfs4",
+				Buildpack: "https://github.com/cloudfoundry/python-buildpack",
+				Env:       map[string]string{"FOO": "bar"},
+			})).ToNot(HaveOccurred())
+
+			manifest := readTestManifest(path)
+			Expect(manifest.Applications[0].Name).To(BeEquivalentTo("python-sample-app"))
+			Expect(manifest.Applications[0].Memory).To(BeEquivalentTo("256M"))
+			Expect(manifest.Applications[0].DiskQuota).To(BeEquivalentTo("256MB"))
+			Expect(manifest.Applications[0].Instances).To(BeEquivalentTo(2))
+			Expect(manifest.Applications[0].Stack).To(BeEquivalentTo("cflinuxfs4"))
+			Expect(manifest.Applications[0].Buildpack).To(BeEquivalentTo(""))
+			Expect(manifest.Applications[0].Buildpacks).To(BeEquivalentTo([]string{"https://github.com/cloudfoundry/python-buildpack"}))
+			Expect(manifest.Applications[0].Env).To(HaveKeyWithValue("FOO", "bar"))
+		})
+
+		It("should create a manifest if there is none", func() {
+			path := filepath.Join(tmpDir, "manifest.yml")
+			Expect(ApplyManifestOverrides(path, "the-app-name", ManifestOverrides{
+				Memory: "512M",
+			})).ToNot(HaveOccurred())
+
+			manifest := readTestManifest(path)
+			Expect(manifest.Applications[0].Name).To(BeEquivalentTo("the-app-name"))
+			Expect(manifest.Applications[0].Memory).To(BeEquivalentTo("512M"))
+		})
+	})
+
+	Context("Merging overrides", func() {
+		It("should let the settings of the other overrides take precedence", func() {
+			result := ManifestOverrides{Memory: "128M", Stack: "cflinuxfs3", Env: map[string]string{"A": "1", "B": "2"}}.
+				Merge(ManifestOverrides{Stack: "cflinuxfs4", Env: map[string]string{"B": "3"}})
+
+			Expect(result.Memory).To(BeEquivalentTo("128M"))
+			Expect(result.Stack).To(BeEquivalentTo("cflinuxfs4"))
+			Expect(result.Env).To(BeEquivalentTo(map[string]string{"A": "1", "B": "3"}))
+			Expect(ManifestOverrides{}.IsEmpty()).To(BeTrue())
+		})
+	})
+
+	Context("Extracting sidecars from a manifest", func() {
+		It("should return the sidecars and remove them from the manifest", func() {
+			data, err := ioutil.ReadFile("../../../assets/sample-apps/sidecar/manifest.yml")
+			Expect(err).ToNot(HaveOccurred())
+
+			path := filepath.Join(tmpDir, "manifest.yml")
+			Expect(ioutil.WriteFile(path, data, 0644)).ToNot(HaveOccurred())
+
+			sidecars, err := ExtractManifestSidecars(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sidecars).To(BeEquivalentTo([]SidecarDefinition{{
+				Name:         "gonut-sidecar",
+				Command:      "node sidecar.js",
+				ProcessTypes: []string{"web"},
+			}}))
+
+			data, err = ioutil.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).ToNot(ContainSubstring("sidecars"))
+			Expect(readTestManifest(path).Applications[0].Name).To(BeEquivalentTo("sidecar-sample-app"))
+		})
+
+		It("should not touch a manifest without sidecars", func() {
+			data, err := ioutil.ReadFile("../../../assets/sample-apps/python/manifest.yml")
+			Expect(err).ToNot(HaveOccurred())
+
+			path := filepath.Join(tmpDir, "manifest.yml")
+			Expect(ioutil.WriteFile(path, data, 0644)).ToNot(HaveOccurred())
+
+			sidecars, err := ExtractManifestSidecars(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sidecars).To(BeNil())
+
+			after, err := ioutil.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(after).To(BeEquivalentTo(data))
+		})
+	})
+})
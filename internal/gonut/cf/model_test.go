@@ -71,6 +71,17 @@ var _ = Describe("Cloud Foundry JSON structs and contracts", func() {
 			Expect(stack.Entity.Description).To(BeEquivalentTo("Cloud Foundry Linux-based filesystem (Ubuntu 18.04)"))
 		})
 
+		It("should parse Cloud Foundry API page of stacks and find the Windows stack", func() {
+			data, err := ioutil.ReadFile("../../../assets/test/cf-curl/v2/stacks/stacks.json")
+			Expect(err).ToNot(HaveOccurred())
+
+			var stacks StacksPage
+			Expect(json.Unmarshal(data, &stacks)).ToNot(HaveOccurred())
+			Expect(len(stacks.Resources)).To(BeEquivalentTo(2))
+			Expect(WindowsStack(stacks.Resources)).To(BeEquivalentTo("windows"))
+			Expect(WindowsStack(stacks.Resources[:1])).To(BeEquivalentTo(""))
+		})
+
 		It("should parse Cloud Foundry API routes details", func() {
 			data, err := ioutil.ReadFile("../../../assets/test/cf-curl/v2/routes/domain-guid.json")
 			Expect(err).ToNot(HaveOccurred())
@@ -90,5 +101,76 @@ var _ = Describe("Cloud Foundry JSON structs and contracts", func() {
 			Expect(domain.Metadata.GUID).To(BeEquivalentTo("75049093-13e9-4520-80a6-2d6fea6542bc"))
 			Expect(domain.Entity.Name).To(BeEquivalentTo("eu-gb.mybluemix.net"))
 		})
+
+		It("should parse Cloud Foundry API app instance stats", func() {
+			data, err := ioutil.ReadFile("../../../assets/test/cf-curl/v2/apps/stats.json")
+			Expect(err).ToNot(HaveOccurred())
+
+			var stats AppStats
+			Expect(json.Unmarshal(data, &stats)).ToNot(HaveOccurred())
+			Expect(len(stats)).To(BeEquivalentTo(2))
+			Expect(stats["0"].State).To(BeEquivalentTo("RUNNING"))
+			Expect(stats["0"].Stats.Host).To(BeEquivalentTo("10.0.16.21"))
+			Expect(stats["0"].Stats.Usage.Mem).To(BeEquivalentTo(8339456))
+			Expect(stats["1"].State).To(BeEquivalentTo("STARTING"))
+		})
+
+		It("should parse Cloud Foundry API droplet details", func() {
+			data, err := ioutil.ReadFile("../../../assets/test/cf-curl/v3/droplets/multi-buildpack.json")
+			Expect(err).ToNot(HaveOccurred())
+
+			var droplet DropletDetails
+			Expect(json.Unmarshal(data, &droplet)).ToNot(HaveOccurred())
+			Expect(len(droplet.Buildpacks)).To(BeEquivalentTo(2))
+			Expect(droplet.Buildpacks[0].Name).To(BeEquivalentTo("nodejs_buildpack"))
+			Expect(droplet.Buildpacks[1].Name).To(BeEquivalentTo("go_buildpack"))
+		})
+
+		It("should parse Cloud Foundry API feature flag details", func() {
+			data, err := ioutil.ReadFile("../../../assets/test/cf-curl/v2/config/feature_flags/diego_docker.json")
+			Expect(err).ToNot(HaveOccurred())
+
+			var featureFlag FeatureFlagDetails
+			Expect(json.Unmarshal(data, &featureFlag)).ToNot(HaveOccurred())
+			Expect(featureFlag.Name).To(BeEquivalentTo("diego_docker"))
+			Expect(featureFlag.Enabled).To(BeTrue())
+		})
+
+		It("should parse Cloud Foundry API page of task details", func() {
+			data, err := ioutil.ReadFile("../../../assets/test/cf-curl/v3/tasks/tasks.json")
+			Expect(err).ToNot(HaveOccurred())
+
+			var tasks TasksPage
+			Expect(json.Unmarshal(data, &tasks)).ToNot(HaveOccurred())
+			Expect(len(tasks.Resources)).To(BeEquivalentTo(1))
+			Expect(tasks.Resources[0].Name).To(BeEquivalentTo("gonut-task"))
+			Expect(tasks.Resources[0].State).To(BeEquivalentTo("SUCCEEDED"))
+			Expect(tasks.Resources[0].Result.FailureReason).To(BeNil())
+		})
+
+		It("should parse Cloud Foundry API service instance details", func() {
+			data, err := ioutil.ReadFile("../../../assets/test/cf-curl/v2/service_instances/managed.json")
+			Expect(err).ToNot(HaveOccurred())
+
+			var serviceInstance ServiceInstanceDetails
+			Expect(json.Unmarshal(data, &serviceInstance)).ToNot(HaveOccurred())
+			Expect(serviceInstance.Metadata.GUID).To(BeEquivalentTo("2f3b9c3e-9c4a-4a6f-8d55-3f2c4e6b8a11"))
+			Expect(serviceInstance.Entity.LastOperation.Type).To(BeEquivalentTo("create"))
+			Expect(serviceInstance.Entity.LastOperation.State).To(BeEquivalentTo("succeeded"))
+		})
+
+		It("should parse sample app runtime details", func() {
+			data, err := ioutil.ReadFile("../../../assets/test/runtime/golang.json")
+			Expect(err).ToNot(HaveOccurred())
+
+			var runtime RuntimeInfo
+			Expect(json.Unmarshal(data, &runtime)).ToNot(HaveOccurred())
+			Expect(runtime.Language).To(BeEquivalentTo("go"))
+			Expect(runtime.RuntimeVersion).To(BeEquivalentTo("go1.12.9"))
+			Expect(runtime.InstanceIndex).To(BeEquivalentTo("0"))
+			Expect(runtime.VCAPApplication).ToNot(BeNil())
+			Expect(runtime.VCAPApplication.SpaceName).To(BeEquivalentTo("gonut"))
+			Expect(runtime.VCAPApplication.Limits.Memory).To(BeEquivalentTo(64))
+		})
 	})
 })
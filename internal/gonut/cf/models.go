@@ -37,6 +37,76 @@ const (
 	OnSuccess
 )
 
+// PushSettings bundles the settings that control how a sample app is pushed,
+// a timeout of zero means that there is no time limit
+type PushSettings struct {
+	CleanupSetting AppCleanupSetting
+	CleanupTimeout time.Duration
+	NoPing         bool
+
+	StagingTimeout time.Duration
+	StartTimeout   time.Duration
+	TotalTimeout   time.Duration
+
+	// DockerImage is pushed instead of the sample app files if set, the
+	// registry password is read by the CF CLI from CF_DOCKER_PASSWORD
+	DockerImage    string
+	DockerUsername string
+
+	// Overrides replace settings of the sample app manifest before the push
+	Overrides ManifestOverrides
+
+	// Buildpacks is the ordered list of buildpacks the app is staged with, if
+	// empty the buildpack is taken from the manifest or detected
+	Buildpacks []string
+
+	// CrashRecovery enables the check whether the app comes back after it
+	// crashed, which fails if it takes longer than the threshold
+	CrashRecovery          bool
+	CrashRecoveryThreshold time.Duration
+
+	// LifecycleOperations run in the given order after a successful push,
+	// supported operations are restart, restage, and scale
+	LifecycleOperations []string
+
+	// RollingDeployment pushes the app again with the rolling strategy while
+	// its route is probed to verify that there is no downtime
+	RollingDeployment bool
+
+	// RuntimeInfo fetches the runtime details the sample app reports on its
+	// /runtime endpoint, it is not used if pinging is disabled
+	RuntimeInfo bool
+
+	// SSHCheck runs a command in the app container using cf ssh
+	SSHCheck bool
+
+	// RunTask runs a one-off task on the droplet of the app
+	RunTask bool
+
+	// Sidecars verifies the sidecars declared in the sample app manifest
+	Sidecars bool
+
+	// LogStreaming verifies that a log line of the app shows up in the live
+	// log stream
+	LogStreaming bool
+
+	// ContainerStats verifies the resource usage metrics of the app container
+	ContainerStats bool
+
+	// Load sends steady traffic to the app route after the push, it is not
+	// used if it is empty
+	Load LoadSettings
+
+	// Protocols checks the WebSocket upgrade and HTTP/2 support of the route
+	// and the HTTP/2 connection from the router to the app
+	Protocols bool
+
+	// ServiceOffering and ServicePlan define a marketplace service that is
+	// created and bound to the app to verify that its credentials arrive
+	ServiceOffering string
+	ServicePlan     string
+}
+
 // CloudFoundryConfig defines the structure used by the Cloud Foundry CLI configuration JSONs
 type CloudFoundryConfig struct {
 	ConfigVersion         int    `json:"ConfigVersion"`
@@ -185,6 +255,15 @@ type BuildpackPage struct {
 	Resources    []BuildpackDetails `json:"resources"`
 }
 
+// StacksPage is the Go struct for the /v2/stacks result JSON
+type StacksPage struct {
+	TotalResults int            `json:"total_results"`
+	TotalPages   int            `json:"total_pages"`
+	PrevURL      string         `json:"prev_url"`
+	NextURL      string         `json:"next_url"`
+	Resources    []StackDetails `json:"resources"`
+}
+
 // RouteDetails is the Go struct for the /v2/apps/<guid>/routes result JSON
 type RouteDetails struct {
 	Metadata struct {
@@ -231,3 +310,117 @@ type DomainDetails struct {
 		RouterGroupType interface{} `json:"router_group_type"`
 	} `json:"entity"`
 }
+
+// OrgsPage represents the result of cf curl /v2/users/<guid>/managed_organizations output
+type OrgsPage struct {
+	NextURL   string `json:"next_url"`
+	Resources []struct {
+		Metadata struct {
+			GUID string `json:"guid"`
+		} `json:"metadata"`
+		Entity struct {
+			Name string `json:"name"`
+		} `json:"entity"`
+	} `json:"resources"`
+}
+
+// FeatureFlagDetails is the Go struct for the /v2/config/feature_flags/<name> result JSON
+type FeatureFlagDetails struct {
+	Name         string      `json:"name"`
+	Enabled      bool        `json:"enabled"`
+	ErrorMessage interface{} `json:"error_message"`
+	URL          string      `json:"url"`
+}
+
+// DropletDetails is the Go struct for the /v3/apps/<guid>/droplets/current result JSON
+type DropletDetails struct {
+	GUID       string `json:"guid"`
+	State      string `json:"state"`
+	Stack      string `json:"stack"`
+	Buildpacks []struct {
+		Name          string `json:"name"`
+		DetectOutput  string `json:"detect_output"`
+		BuildpackName string `json:"buildpack_name"`
+		Version       string `json:"version"`
+	} `json:"buildpacks"`
+}
+
+// TasksPage is the Go struct for the /v3/apps/<guid>/tasks result JSON
+type TasksPage struct {
+	Resources []TaskDetails `json:"resources"`
+}
+
+// TaskDetails is the Go struct for a task in the /v3/apps/<guid>/tasks result JSON
+type TaskDetails struct {
+	GUID       string `json:"guid"`
+	SequenceID int    `json:"sequence_id"`
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	Result     struct {
+		FailureReason *string `json:"failure_reason"`
+	} `json:"result"`
+	DropletGUID string    `json:"droplet_guid"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ServiceInstanceDetails is the Go struct for the /v2/service_instances/<guid> result JSON
+type ServiceInstanceDetails struct {
+	Metadata struct {
+		GUID string `json:"guid"`
+	} `json:"metadata"`
+	Entity struct {
+		Name          string `json:"name"`
+		LastOperation struct {
+			Type        string `json:"type"`
+			State       string `json:"state"`
+			Description string `json:"description"`
+		} `json:"last_operation"`
+	} `json:"entity"`
+}
+
+// RuntimeInfo is the Go struct for the /runtime result JSON of the sample apps
+type RuntimeInfo struct {
+	Language        string `json:"language"`
+	RuntimeVersion  string `json:"runtime_version"`
+	InstanceIndex   string `json:"instance_index"`
+	MemoryLimit     string `json:"memory_limit"`
+	Stack           string `json:"stack"`
+	VCAPApplication *struct {
+		ApplicationID    string   `json:"application_id"`
+		ApplicationName  string   `json:"application_name"`
+		ApplicationURIs  []string `json:"application_uris"`
+		SpaceName        string   `json:"space_name"`
+		OrganizationName string   `json:"organization_name"`
+		Limits           struct {
+			Memory int `json:"mem"`
+			Disk   int `json:"disk"`
+			FDs    int `json:"fds"`
+		} `json:"limits"`
+	} `json:"vcap_application"`
+}
+
+// AppStats is the Go struct for the /v2/apps/<guid>/stats result JSON, which
+// maps the instance index to the instance stats
+type AppStats map[string]InstanceStats
+
+// InstanceStats contains the state and usage details of one app instance
+type InstanceStats struct {
+	State string `json:"state"`
+	Stats struct {
+		Name      string   `json:"name"`
+		URIs      []string `json:"uris"`
+		Host      string   `json:"host"`
+		Port      int      `json:"port"`
+		Uptime    int      `json:"uptime"`
+		MemQuota  int64    `json:"mem_quota"`
+		DiskQuota int64    `json:"disk_quota"`
+		FdsQuota  int      `json:"fds_quota"`
+		Usage     struct {
+			Time string  `json:"time"`
+			CPU  float64 `json:"cpu"`
+			Mem  int64   `json:"mem"`
+			Disk int64   `json:"disk"`
+		} `json:"usage"`
+	} `json:"stats"`
+}
@@ -0,0 +1,225 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gonvenience/wait"
+	"github.com/homeport/gonut/internal/gonut/nok"
+	"github.com/homeport/pina-golada/pkg/files"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// InternalDomain is the Cloud Foundry domain used for internal routes that are
+// only reachable through the container network
+const InternalDomain = "apps.internal"
+
+// ProxyEnabledEnv is the environment variable that enables the proxy endpoint
+// of the Go sample app, which is only set for the frontend app of the
+// networking check
+const ProxyEnabledEnv = "GONUT_PROXY_ENABLED"
+
+// networkPolicyPort is the port the backend app listens on inside the container
+const networkPolicyPort = 8080
+
+// networkPolicyTimeout is the maximum time a network policy may take until it
+// is in effect
+const networkPolicyTimeout = 2 * time.Minute
+
+// networkPolicyPollInterval is the time to wait between two attempts to reach
+// the backend app through the frontend app
+const networkPolicyPollInterval = time.Second
+
+// NetworkingReport encapsules details of the container-to-container networking
+// check, in which a frontend app connects to a backend app on an internal route
+type NetworkingReport struct {
+	Frontend *PushReport
+	Backend  *PushReport
+
+	InternalRoute string
+	PolicyStart   time.Time
+	PolicyEnd     time.Time
+}
+
+// PropagationTime is the time it takes from adding the network policy until
+// the backend app is reachable from the frontend app
+func (report NetworkingReport) PropagationTime() time.Duration {
+	return phaseDuration(report.PolicyStart, report.PolicyEnd)
+}
+
+// ElapsedTime is the overall elapsed time of the networking check
+func (report NetworkingReport) ElapsedTime() time.Duration {
+	if report.Backend == nil || report.PolicyEnd.IsZero() {
+		return time.Duration(0)
+	}
+
+	return report.PolicyEnd.Sub(report.Backend.InitStart)
+}
+
+// Export creates a less technical representation of the report
+func (report *NetworkingReport) Export() yaml.MapSlice {
+	result := yaml.MapSlice{}
+
+	if report.Backend != nil {
+		result = append(result, yaml.MapItem{Key: "backend push", Value: report.Backend.ElapsedTime()})
+	}
+
+	if report.Frontend != nil {
+		result = append(result, yaml.MapItem{Key: "frontend push", Value: report.Frontend.ElapsedTime()})
+	}
+
+	if len(report.InternalRoute) > 0 {
+		result = append(result, yaml.MapItem{Key: "internal route", Value: report.InternalRoute})
+	}
+
+	result = append(result,
+		yaml.MapItem{Key: "policy propagation", Value: report.PropagationTime()},
+		yaml.MapItem{Key: "reachable", Value: !report.PolicyEnd.IsZero()},
+	)
+
+	return result
+}
+
+// ExportTable creates a less technical representation of the report in form of
+// a two-dimensional array
+func (report *NetworkingReport) ExportTable() [][]string {
+	return exportTable(report.Export())
+}
+
+// VerifyContainerNetworking pushes the sample app twice, maps an internal route
+// to the backend app, adds a network policy from the frontend to the backend
+// app, and measures how long it takes until the backend app is reachable
+// through the proxy endpoint of the frontend app
+func VerifyContainerNetworking(ctx context.Context, caption string, frontendName string, backendName string, directory files.Directory, settings PushSettings) (*NetworkingReport, error) {
+	report := &NetworkingReport{}
+
+	// The apps are only removed once the networking check is done
+	cleanupSetting := settings.CleanupSetting
+	settings.CleanupSetting = Never
+
+	var (
+		success bool
+		pushed  []string
+		policy  bool
+	)
+
+	defer func() {
+		if cleanupSetting == Never || (cleanupSetting == OnSuccess && !success) {
+			return
+		}
+
+		cleanupCtx, cancel := CleanupContext(settings.CleanupTimeout)
+		defer cancel()
+
+		if policy {
+			cf(cleanupCtx, nil, "remove-network-policy", frontendName, "--destination-app", backendName, "--protocol", "tcp", "--port", fmt.Sprint(networkPolicyPort))
+		}
+
+		for _, appName := range pushed {
+			cf(cleanupCtx, nil, "delete", appName, "-r", "-f")
+		}
+	}()
+
+	var err error
+
+	pushed = append(pushed, backendName)
+	if report.Backend, err = PushApp(ctx, fmt.Sprintf("%s backend", caption), backendName, directory, settings); err != nil {
+		return report, err
+	}
+
+	// Only the frontend app forwards requests to the backend app
+	frontendSettings := settings
+	frontendSettings.Overrides = settings.Overrides.Merge(ManifestOverrides{
+		Env: map[string]string{ProxyEnabledEnv: "true"},
+	})
+
+	pushed = append(pushed, frontendName)
+	if report.Frontend, err = PushApp(ctx, fmt.Sprintf("%s frontend", caption), frontendName, directory, frontendSettings); err != nil {
+		return report, err
+	}
+
+	spinner := wait.NewProgressIndicator("*%s*, DimGray{%s}", caption, "Networking")
+	spinner.Start()
+	defer spinner.Stop()
+
+	step := func(name string, text string) {
+		spinner.SetText("*%s*, DimGray{%s} - %s", caption, name, text)
+	}
+
+	report.InternalRoute = fmt.Sprintf("%s.%s", backendName, InternalDomain)
+	step("Mapping", report.InternalRoute)
+	if output, err := cf(ctx, nil, "map-route", backendName, InternalDomain, "--hostname", backendName); err != nil {
+		return report, operationError(ctx,
+			fmt.Sprintf("failed to map internal route %s to application %s", report.InternalRoute, backendName),
+			output,
+		)
+	}
+
+	frontendRoute, err := getAppRoute(ctx, frontendName)
+	if err != nil {
+		return report, nok.Errorf(
+			fmt.Sprintf("failed to get url of application %s from Cloud Foundry", frontendName),
+			err.Error(),
+		)
+	}
+
+	step("Policy", fmt.Sprintf("%s to %s", frontendName, backendName))
+	report.PolicyStart = time.Now()
+	if output, err := cf(ctx, nil, "add-network-policy", frontendName, "--destination-app", backendName, "--protocol", "tcp", "--port", fmt.Sprint(networkPolicyPort)); err != nil {
+		return report, operationError(ctx,
+			fmt.Sprintf("failed to add network policy from application %s to %s", frontendName, backendName),
+			output,
+		)
+	}
+	policy = true
+
+	step("Propagation", fmt.Sprintf("waiting for %s to be reachable", report.InternalRoute))
+	proxyURL := fmt.Sprintf("%s/proxy/%s:%d", frontendRoute, report.InternalRoute, networkPolicyPort)
+	for {
+		if statusCode, err := getAppStatusCode(ctx, proxyURL); err == nil && statusCode == http.StatusOK {
+			report.PolicyEnd = time.Now()
+			break
+		}
+
+		if time.Since(report.PolicyStart) > networkPolicyTimeout {
+			return report, nok.Errorf(
+				fmt.Sprintf("application %s is not reachable from application %s", backendName, frontendName),
+				"The backend app was not reachable on %s through the container network within %s after the network policy was added.",
+				report.InternalRoute,
+				networkPolicyTimeout,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+
+		case <-time.After(networkPolicyPollInterval):
+		}
+	}
+
+	success = true
+	return report, nil
+}
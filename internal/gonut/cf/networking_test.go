@@ -0,0 +1,63 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	yaml "gopkg.in/yaml.v2"
+
+	. "github.com/homeport/gonut/internal/gonut/cf"
+)
+
+var _ = Describe("Cloud Foundry container networking report", func() {
+	Context("Export container networking reports", func() {
+		It("should list the pushes and the policy propagation time", func() {
+			start := time.Now()
+			report := &NetworkingReport{
+				Backend:       &PushReport{InitStart: start, PushEnd: start.Add(40 * time.Second)},
+				Frontend:      &PushReport{InitStart: start.Add(40 * time.Second), PushEnd: start.Add(75 * time.Second)},
+				InternalRoute: "the-backend." + InternalDomain,
+				PolicyStart:   start.Add(80 * time.Second),
+				PolicyEnd:     start.Add(83 * time.Second),
+			}
+
+			Expect(report.PropagationTime()).To(BeEquivalentTo(3 * time.Second))
+			Expect(report.ElapsedTime()).To(BeEquivalentTo(83 * time.Second))
+			Expect(report.Export()).To(BeEquivalentTo(yaml.MapSlice{
+				{Key: "backend push", Value: 40 * time.Second},
+				{Key: "frontend push", Value: 35 * time.Second},
+				{Key: "internal route", Value: "the-backend.apps.internal"},
+				{Key: "policy propagation", Value: 3 * time.Second},
+				{Key: "reachable", Value: true},
+			}))
+		})
+
+		It("should report an unreachable backend app", func() {
+			report := &NetworkingReport{PolicyStart: time.Now()}
+
+			Expect(report.PropagationTime()).To(BeEquivalentTo(time.Duration(0)))
+			Expect(report.Export()).To(ContainElement(yaml.MapItem{Key: "reachable", Value: false}))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+)
+
+// phaseTracker keeps track of the current push phase and cancels the push if a
+// phase takes longer than the timeout that is configured for it
+type phaseTracker struct {
+	sync.Mutex
+
+	cancel   context.CancelFunc
+	timeouts map[string]time.Duration
+	timer    *time.Timer
+	current  string
+	expired  string
+}
+
+func newPhaseTracker(phase string, cancel context.CancelFunc, timeouts map[string]time.Duration) *phaseTracker {
+	tracker := &phaseTracker{
+		cancel:   cancel,
+		timeouts: timeouts,
+	}
+
+	tracker.enter(phase)
+	return tracker
+}
+
+// enter marks the start of the given phase, which stops the timer of the
+// previous phase and starts the timer of the new phase if it has a timeout
+func (t *phaseTracker) enter(phase string) {
+	t.Lock()
+	defer t.Unlock()
+
+	if phase == t.current {
+		return
+	}
+
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+
+	t.current = phase
+
+	if timeout, ok := t.timeouts[phase]; ok && timeout > 0 {
+		t.timer = time.AfterFunc(timeout, func() {
+			t.Lock()
+			if t.current != phase {
+				t.Unlock()
+				return
+			}
+
+			t.expired = phase
+			t.Unlock()
+
+			t.cancel()
+		})
+	}
+}
+
+// stop disables the timer of the current phase
+func (t *phaseTracker) stop() {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+}
+
+// contextError translates the state of a done push context into an error
+// that names the phase the push was in when it was stopped
+func (t *phaseTracker) contextError(appName string, totalCtx context.Context, totalTimeout time.Duration) error {
+	t.Lock()
+	defer t.Unlock()
+
+	switch {
+	case len(t.expired) > 0:
+		return nok.Errorf(
+			fmt.Sprintf("push of application %s timed out in the %s phase", appName, strings.ToLower(t.expired)),
+			"The %s phase did not finish within the configured timeout of %s.", strings.ToLower(t.expired), t.timeouts[t.expired],
+		)
+
+	case totalCtx.Err() == context.DeadlineExceeded:
+		return nok.Errorf(
+			fmt.Sprintf("push of application %s timed out in the %s phase", appName, strings.ToLower(t.current)),
+			"The push did not finish within the configured total timeout of %s.", totalTimeout,
+		)
+
+	default:
+		return nok.Errorf(
+			fmt.Sprintf("push of application %s was interrupted in the %s phase", appName, strings.ToLower(t.current)),
+			"The operation was stopped before it could finish: %v", totalCtx.Err(),
+		)
+	}
+}
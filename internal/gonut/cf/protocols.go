@@ -0,0 +1,368 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// protocolCheckTimeout is the maximum time each of the protocol checks may take
+const protocolCheckTimeout = 30 * time.Second
+
+// http2HostnameSuffix is appended to the hostname of the app route to get the
+// hostname of the additional route that uses the http2 protocol
+const http2HostnameSuffix = "-h2"
+
+// websocketGUID is the fixed GUID used to compute the WebSocket accept key
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// ProtocolReport contains the outcome of the WebSocket and HTTP/2 checks
+type ProtocolReport struct {
+	WebSocket     bool
+	WebSocketTime time.Duration
+
+	// HTTP2 is the protocol the foundation negotiated for a TLS request to the
+	// app route, or the reason why HTTP/2 is not offered. It covers the
+	// connection from the client to the load balancer or router.
+	HTTP2     bool
+	HTTP2Info string
+
+	// HTTP2App is the protocol a request on a route using the http2 protocol
+	// reached the sample app with, or the reason why it could not be checked.
+	// It covers the connection from the router to the app.
+	HTTP2App     bool
+	HTTP2AppInfo string
+}
+
+// verifyProtocols checks the WebSocket upgrade through the router using the
+// WebSocket echo endpoint of the sample app, whether the foundation offers
+// HTTP/2 to clients of the app route, and whether the router talks HTTP/2 to
+// the app on a route using the http2 protocol
+func verifyProtocols(ctx context.Context, appName string, appRoute string) (*ProtocolReport, error) {
+	report := &ProtocolReport{}
+
+	start := time.Now()
+	if err := verifyWebSocketEcho(ctx, appRoute); err != nil {
+		return report, operationError(ctx,
+			fmt.Sprintf("WebSocket upgrade of application %s failed", appName),
+			err.Error(),
+		)
+	}
+
+	report.WebSocket = true
+	report.WebSocketTime = time.Since(start)
+
+	report.HTTP2, report.HTTP2Info = checkHTTP2(ctx, appRoute)
+
+	http2App, http2AppInfo, err := checkHTTP2ToApp(ctx, appName, appRoute)
+	report.HTTP2App, report.HTTP2AppInfo = http2App, http2AppInfo
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// verifyWebSocketEcho opens a WebSocket connection to the app and expects the
+// message that is sent to be echoed back
+func verifyWebSocketEcho(ctx context.Context, appRoute string) error {
+	target, err := url.Parse(appRoute)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, protocolCheckTimeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+
+	var conn net.Conn
+	switch target.Scheme {
+	case "https":
+		rawConn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(target.Hostname(), portOrDefault(target, "443")))
+		if err != nil {
+			return err
+		}
+
+		// The deadline has to be in place before the handshake, so that an
+		// unresponsive endpoint cannot block the handshake
+		if deadline, ok := ctx.Deadline(); ok {
+			rawConn.SetDeadline(deadline)
+		}
+
+		tlsConn := tls.Client(rawConn, &tls.Config{ServerName: target.Hostname()})
+		if err := tlsConn.Handshake(); err != nil {
+			rawConn.Close()
+			return err
+		}
+
+		conn = tlsConn
+
+	default:
+		if conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(target.Hostname(), portOrDefault(target, "80"))); err != nil {
+			return err
+		}
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/ws", appRoute), nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err := req.Write(conn); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return fmt.Errorf("the WebSocket upgrade request returned the statuscode %d instead of %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != base64.StdEncoding.EncodeToString(sum[:]) {
+		return fmt.Errorf("the WebSocket upgrade response contains the unexpected accept key %s", accept)
+	}
+
+	message := []byte(fmt.Sprintf("gonut-websocket-%d", time.Now().UnixNano()))
+	if err := writeMaskedFrame(conn, 0x1, message); err != nil {
+		return err
+	}
+
+	payload, err := readFramePayload(reader)
+	if err != nil {
+		return err
+	}
+
+	if string(payload) != string(message) {
+		return fmt.Errorf("the WebSocket echo returned %q instead of %q", payload, message)
+	}
+
+	// Close the WebSocket connection properly, the response is not relevant
+	writeMaskedFrame(conn, 0x8, []byte{0x03, 0xE8})
+
+	return nil
+}
+
+// checkHTTP2 sends a TLS request to the app route and returns whether HTTP/2
+// was negotiated with the client, together with a description of the outcome
+func checkHTTP2(ctx context.Context, appRoute string) (bool, string) {
+	target, err := url.Parse(appRoute)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	target.Scheme = "https"
+
+	ctx, cancel := context.WithTimeout(ctx, protocolCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Sprintf("not offered, TLS request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.ProtoMajor == 2 {
+		return true, resp.Proto
+	}
+
+	return false, fmt.Sprintf("not offered, negotiated %s", resp.Proto)
+}
+
+// checkHTTP2ToApp maps an additional route using the http2 protocol to the app
+// and returns whether a request on that route reached the app using HTTP/2,
+// together with a description of the outcome. Foundations or CF CLI versions
+// that do not support the http2 route protocol are reported, but not treated
+// as an error.
+func checkHTTP2ToApp(ctx context.Context, appName string, appRoute string) (bool, string, error) {
+	target, err := url.Parse(appRoute)
+	if err != nil {
+		return false, "", err
+	}
+
+	labels := strings.SplitN(target.Hostname(), ".", 2)
+	if len(labels) != 2 {
+		return false, fmt.Sprintf("not checked, route %s has no domain", appRoute), nil
+	}
+
+	hostname, domain := labels[0]+http2HostnameSuffix, labels[1]
+	if _, err := cf(ctx, nil, "map-route", appName, domain, "--hostname", hostname, "--app-protocol", "http2"); err != nil {
+		if ctx.Err() != nil {
+			return false, "", ctx.Err()
+		}
+
+		return false, "not offered, the http2 route protocol is not supported", nil
+	}
+
+	// The additional route is mapped to the app, so that it is deleted
+	// together with the app and its routes
+	http2Route := fmt.Sprintf("%s://%s.%s", target.Scheme, hostname, domain)
+	if _, err := waitForRouteRegistration(ctx, appName, http2Route); err != nil {
+		return false, "", err
+	}
+
+	protocol, err := getAppProtocol(ctx, http2Route)
+	if err != nil {
+		return false, "", operationError(ctx,
+			fmt.Sprintf("failed to get the protocol of the request to application %s", appName),
+			err.Error(),
+		)
+	}
+
+	if protocol == "HTTP/2.0" {
+		return true, protocol, nil
+	}
+
+	return false, fmt.Sprintf("not used, the router connected using %s", protocol), nil
+}
+
+// getAppProtocol requests the protocol endpoint of the sample app, which
+// returns the protocol the request reached the app with
+func getAppProtocol(ctx context.Context, appRoute string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, protocolCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/protocol", appRoute), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("the protocol endpoint returned the statuscode %d", resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func portOrDefault(target *url.URL, port string) string {
+	if target.Port() != "" {
+		return target.Port()
+	}
+
+	return port
+}
+
+func writeMaskedFrame(w io.Writer, opcode byte, payload []byte) error {
+	if len(payload) > 125 {
+		return fmt.Errorf("WebSocket payload too large")
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := w.Write(frame)
+	return err
+}
+
+func readFramePayload(r *bufio.Reader) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	if length > 1<<20 {
+		return nil, fmt.Errorf("WebSocket frame too large")
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
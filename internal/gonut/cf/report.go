@@ -31,7 +31,8 @@ import (
 
 // PushReport encapsules details of a Cloud Foundry push command
 type PushReport struct {
-	AppName string
+	AppName     string
+	DockerImage string
 
 	InitStart      time.Time
 	CreatingStart  time.Time
@@ -40,9 +41,36 @@ type PushReport struct {
 	StartingStart  time.Time
 	PushEnd        time.Time
 
+	// RouteRegisteredEnd is the time the app route first answered with the
+	// statuscode 200 after the app was reported as running
+	RouteRegisteredEnd time.Time
+
 	buildpack  *BuildpackDetails
+	buildpacks []string
 	stack      *StackDetails
 	StatusCode int
+
+	// Runtime contains the details the sample app reports about its runtime
+	Runtime *RuntimeInfo
+
+	Instances []InstanceReport
+
+	CrashRecoveryTime      time.Duration
+	CrashRecoveryThreshold time.Duration
+
+	SSH       *SSHReport
+	Task      *TaskReport
+	Sidecar   *SidecarReport
+	Logs      *LogReport
+	Stats     *ContainerStats
+	Load      *LoadReport
+	Protocols *ProtocolReport
+
+	Service *ServiceReport
+
+	Lifecycle []LifecycleReport
+
+	RollingDeployment *RollingDeploymentReport
 }
 
 // InitTime is the time it takes to initialise the Cloud Foundry app push setup
@@ -52,6 +80,11 @@ func (report PushReport) InitTime() time.Duration {
 
 // CreatingTime is the time it takes to create the app in Cloud Foundry
 func (report PushReport) CreatingTime() time.Duration {
+	// Docker image based apps have no upload step
+	if report.IsDockerImage() {
+		return report.StagingStart.Sub(report.CreatingStart)
+	}
+
 	return report.UploadingStart.Sub(report.CreatingStart)
 }
 
@@ -70,11 +103,22 @@ func (report PushReport) StartingTime() time.Duration {
 	return report.PushEnd.Sub(report.StartingStart)
 }
 
+// RouteRegistrationTime is the time it takes from the app being reported as
+// running until its route answers through the router
+func (report PushReport) RouteRegistrationTime() time.Duration {
+	return phaseDuration(report.PushEnd, report.RouteRegisteredEnd)
+}
+
 // ElapsedTime is the overall elapsed time it takes to push an app in Cloud Foundry
 func (report PushReport) ElapsedTime() time.Duration {
 	return report.PushEnd.Sub(report.InitStart)
 }
 
+// IsDockerImage returns true if the report is about a Docker image based app
+func (report PushReport) IsDockerImage() bool {
+	return len(report.DockerImage) > 0
+}
+
 // Buildpack provides the name of the buildpack used (if detectable)
 func (report PushReport) Buildpack() string {
 	if report.buildpack != nil {
@@ -126,7 +170,7 @@ func (report *PushReport) ParseUpdate(text string) string {
 func (report *PushReport) HasTimeDetails() bool {
 	return report.InitTime() > time.Duration(0) &&
 		report.CreatingTime() > time.Duration(0) &&
-		report.UploadingTime() > time.Duration(0) &&
+		(report.IsDockerImage() || report.UploadingTime() > time.Duration(0)) &&
 		report.StagingTime() > time.Duration(0) &&
 		report.StartingTime() > time.Duration(0)
 }
@@ -138,13 +182,63 @@ func (report *PushReport) Export() yaml.MapSlice {
 		yaml.MapItem{Key: "buildpack", Value: report.Buildpack()},
 	}
 
+	if report.IsDockerImage() {
+		result = yaml.MapSlice{
+			yaml.MapItem{Key: "docker image", Value: report.DockerImage},
+		}
+	}
+
+	if len(report.buildpacks) > 1 {
+		result = append(result,
+			yaml.MapItem{Key: "buildpacks", Value: report.buildpacks},
+		)
+	}
+
 	if report.StatusCode != 0 {
 		result = append(result,
 			yaml.MapItem{Key: "statuscode", Value: report.StatusCode},
 		)
 	}
 
-	if report.HasTimeDetails() {
+	if report.Runtime != nil {
+		result = append(result,
+			yaml.MapItem{Key: "runtime", Value: strings.TrimSpace(report.Runtime.Language + " " + report.Runtime.RuntimeVersion)},
+		)
+
+		// The remaining details are reported as the app sees them at runtime
+		for _, item := range []yaml.MapItem{
+			{Key: "memory limit", Value: report.Runtime.MemoryLimit},
+			{Key: "instance index", Value: report.Runtime.InstanceIndex},
+			{Key: "runtime stack", Value: report.Runtime.Stack},
+		} {
+			if len(item.Value.(string)) > 0 {
+				result = append(result, item)
+			}
+		}
+
+		if vcap := report.Runtime.VCAPApplication; vcap != nil {
+			result = append(result,
+				yaml.MapItem{Key: "application id", Value: vcap.ApplicationID},
+				yaml.MapItem{Key: "org and space", Value: fmt.Sprintf("%s/%s", vcap.OrganizationName, vcap.SpaceName)},
+				yaml.MapItem{Key: "application uris", Value: strings.Join(vcap.ApplicationURIs, ", ")},
+				yaml.MapItem{Key: "memory quota", Value: HumanReadableSize(int64(vcap.Limits.Memory) * 1024 * 1024)},
+				yaml.MapItem{Key: "disk quota", Value: HumanReadableSize(int64(vcap.Limits.Disk) * 1024 * 1024)},
+			)
+		}
+	}
+
+	switch {
+	case report.HasTimeDetails() && report.IsDockerImage():
+		// Staging of Docker image based apps only fetches the image metadata,
+		// the image itself is pulled on the cell when the app is starting
+		result = append(result,
+			yaml.MapItem{Key: "ramp-up", Value: report.InitTime()},
+			yaml.MapItem{Key: "creating", Value: report.CreatingTime()},
+			yaml.MapItem{Key: "staging", Value: report.StagingTime()},
+			yaml.MapItem{Key: "image pull and start", Value: report.StartingTime()},
+		)
+
+	case report.HasTimeDetails():
 		result = append(result,
 			yaml.MapItem{Key: "ramp-up", Value: report.InitTime()},
 			yaml.MapItem{Key: "creating", Value: report.CreatingTime()},
@@ -154,14 +248,136 @@ func (report *PushReport) Export() yaml.MapSlice {
 		)
 	}
 
+	if !report.RouteRegisteredEnd.IsZero() {
+		result = append(result,
+			yaml.MapItem{Key: "route registration", Value: report.RouteRegistrationTime()},
+		)
+	}
+
+	if report.Stats != nil {
+		result = append(result,
+			yaml.MapItem{Key: "cpu usage", Value: fmt.Sprintf("%.2f%%", report.Stats.CPU*100)},
+			yaml.MapItem{Key: "memory usage", Value: fmt.Sprintf("%s of %s", HumanReadableSize(report.Stats.Memory), HumanReadableSize(report.Stats.MemoryQuota))},
+			yaml.MapItem{Key: "disk usage", Value: fmt.Sprintf("%s of %s", HumanReadableSize(report.Stats.Disk), HumanReadableSize(report.Stats.DiskQuota))},
+			yaml.MapItem{Key: "uptime", Value: report.Stats.Uptime},
+		)
+	}
+
+	if report.Protocols != nil {
+		result = append(result,
+			yaml.MapItem{Key: "websocket", Value: report.Protocols.WebSocket},
+			yaml.MapItem{Key: "websocket echo", Value: report.Protocols.WebSocketTime},
+			yaml.MapItem{Key: "http/2 to router", Value: report.Protocols.HTTP2Info},
+			yaml.MapItem{Key: "http/2 to app", Value: report.Protocols.HTTP2AppInfo},
+		)
+	}
+
+	if report.SSH != nil {
+		if report.SSH.Skipped() {
+			result = append(result,
+				yaml.MapItem{Key: "ssh", Value: fmt.Sprintf("skipped, %s", report.SSH.SkipReason)},
+			)
+
+		} else {
+			result = append(result,
+				yaml.MapItem{Key: "ssh session", Value: report.SSH.SessionTime},
+				yaml.MapItem{Key: "ssh passed", Value: report.SSH.Passed},
+			)
+		}
+	}
+
+	if report.Task != nil {
+		result = append(result,
+			yaml.MapItem{Key: "task scheduling", Value: report.Task.SchedulingTime()},
+			yaml.MapItem{Key: "task execution", Value: report.Task.ExecutionTime()},
+			yaml.MapItem{Key: "task state", Value: report.Task.State},
+		)
+	}
+
+	if report.Sidecar != nil {
+		result = append(result,
+			yaml.MapItem{Key: "sidecars", Value: strings.Join(report.Sidecar.Names, ", ")},
+			yaml.MapItem{Key: "main process running", Value: report.Sidecar.MainRunning},
+			yaml.MapItem{Key: "sidecar process running", Value: report.Sidecar.SidecarRunning},
+			yaml.MapItem{Key: "sidecar reachable", Value: report.Sidecar.Reachable},
+		)
+	}
+
+	if report.Logs != nil {
+		if report.Logs.Lost {
+			result = append(result, yaml.MapItem{Key: "log latency", Value: "lost"})
+
+		} else {
+			result = append(result, yaml.MapItem{Key: "log latency", Value: report.Logs.Latency})
+		}
+	}
+
+	if report.CrashRecoveryTime > 0 {
+		result = append(result,
+			yaml.MapItem{Key: "crash recovery", Value: report.CrashRecoveryTime},
+			yaml.MapItem{Key: "crash recovery passed", Value: report.CrashRecoveryTime <= report.CrashRecoveryThreshold},
+		)
+	}
+
+	if len(report.Instances) > 0 {
+		instances := make([]yaml.MapSlice, 0, len(report.Instances))
+		for _, instance := range report.Instances {
+			entry := yaml.MapSlice{
+				yaml.MapItem{Key: "index", Value: instance.Index},
+				yaml.MapItem{Key: "state", Value: instance.State},
+				yaml.MapItem{Key: "cell", Value: instance.Cell},
+				yaml.MapItem{Key: "since", Value: instance.Since.Format(time.RFC3339)},
+			}
+
+			if instance.StatusCode != 0 {
+				entry = append(entry, yaml.MapItem{Key: "statuscode", Value: instance.StatusCode})
+			}
+
+			instances = append(instances, entry)
+		}
+
+		result = append(result, yaml.MapItem{Key: "instances", Value: instances})
+	}
+
+	if report.Load != nil {
+		result = append(result,
+			yaml.MapItem{Key: "load", Value: report.Load.Export()},
+		)
+	}
+
+	if report.Service != nil {
+		result = append(result,
+			yaml.MapItem{Key: "service", Value: report.Service.Export()},
+		)
+	}
+
+	// Each lifecycle operation is a separate section of the report
+	for i := range report.Lifecycle {
+		result = append(result,
+			yaml.MapItem{Key: report.Lifecycle[i].Operation, Value: report.Lifecycle[i].Export()},
+		)
+	}
+
+	if report.RollingDeployment != nil {
+		result = append(result,
+			yaml.MapItem{Key: report.RollingDeployment.Operation, Value: report.RollingDeployment.Export()},
+		)
+	}
+
 	return result
 }
 
 // ExportTable creates a less technical representation of the report in form of
 // a two-dimensional array
 func (report *PushReport) ExportTable() [][]string {
+	return exportTable(report.Export())
+}
+
+// exportTable creates the two-dimensional array representation of an exported
+// report, nested sections are left out since they are shown separately
+func exportTable(export yaml.MapSlice) [][]string {
 	result := [][]string{}
-	for _, item := range report.Export() {
+	for _, item := range export {
 		var (
 			key   string = bunt.Sprintf("DimGray{_%v_}", item.Key)
 			value string
@@ -171,6 +387,29 @@ func (report *PushReport) ExportTable() [][]string {
 		case time.Duration:
 			value = bunt.Sprintf("SteelBlue{%v}", HumanReadableDuration(obj))
 
+		case []string:
+			value = bunt.Sprintf("DarkSeaGreen{%v}", strings.Join(obj, ", "))
+
+		case yaml.MapSlice:
+			continue
+
+		case []yaml.MapSlice:
+			// One row per entry, the key is only shown in the first row
+			for idx, entry := range obj {
+				parts := make([]string, 0, len(entry))
+				for _, field := range entry {
+					parts = append(parts, fmt.Sprintf("%v: %v", field.Key, field.Value))
+				}
+
+				if idx > 0 {
+					key = ""
+				}
+
+				result = append(result, []string{key, bunt.Sprintf("DarkSeaGreen{%v}", strings.Join(parts, ", "))})
+			}
+
+			continue
+
 		default:
 			value = bunt.Sprintf("DarkSeaGreen{%v}", fmt.Sprintf("%v", obj))
 		}
@@ -22,11 +22,14 @@ package cf_test
 
 import (
 	"bufio"
+	"encoding/json"
+	"io/ioutil"
 	"os"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	yaml "gopkg.in/yaml.v2"
 
 	. "github.com/homeport/gonut/internal/gonut/cf"
 )
@@ -112,4 +115,249 @@ var _ = Describe("Cloud Foundry push report", func() {
 			Expect(report.PushEnd).ToNot(BeEquivalentTo(unset))
 		})
 	})
+
+	Context("Export multi-instance push reports", func() {
+		It("should list one table row per app instance", func() {
+			report := &PushReport{
+				AppName: "the-app-name",
+				Instances: []InstanceReport{
+					{Index: 0, State: "RUNNING", Cell: "10.0.16.21", Since: time.Now(), StatusCode: 200},
+					{Index: 1, State: "RUNNING", Cell: "10.0.16.34", Since: time.Now(), StatusCode: 200},
+				},
+			}
+
+			table := report.ExportTable()
+			Expect(len(table)).To(BeEquivalentTo(4))
+			Expect(table[2][1]).To(ContainSubstring("10.0.16.21"))
+			Expect(table[3][0]).To(BeEquivalentTo(""))
+			Expect(table[3][1]).To(ContainSubstring("10.0.16.34"))
+		})
+	})
+
+	Context("Export Docker image based push reports", func() {
+		It("should report the image and skip the uploading step", func() {
+			start := time.Now()
+			report := &PushReport{
+				AppName:       "the-app-name",
+				DockerImage:   "cloudfoundry/diego-docker-app:latest",
+				InitStart:     start,
+				CreatingStart: start.Add(1 * time.Second),
+				StagingStart:  start.Add(2 * time.Second),
+				StartingStart: start.Add(4 * time.Second),
+				PushEnd:       start.Add(8 * time.Second),
+			}
+
+			Expect(report.HasTimeDetails()).To(BeTrue())
+
+			keys := []interface{}{}
+			for _, item := range report.Export() {
+				keys = append(keys, item.Key)
+			}
+
+			Expect(keys).To(ContainElement("docker image"))
+			Expect(keys).To(ContainElement("image pull and start"))
+			Expect(keys).ToNot(ContainElement("uploading"))
+		})
+	})
+
+	Context("Export service binding reports", func() {
+		It("should list the service and the times of the broker operations", func() {
+			report := &PushReport{
+				AppName: "the-app-name",
+				Service: &ServiceReport{
+					Offering:        "p.mysql",
+					Plan:            "db-small",
+					ProvisionTime:   4 * time.Minute,
+					BindTime:        2 * time.Second,
+					UnbindTime:      1 * time.Second,
+					DeprovisionTime: 90 * time.Second,
+				},
+			}
+
+			export := report.Export()
+			service := export[len(export)-1]
+			Expect(service.Key).To(BeEquivalentTo("service"))
+			Expect(service.Value).To(BeEquivalentTo(yaml.MapSlice{
+				{Key: "offering", Value: "p.mysql"},
+				{Key: "plan", Value: "db-small"},
+				{Key: "provision", Value: 4 * time.Minute},
+				{Key: "bind", Value: 2 * time.Second},
+				{Key: "unbind", Value: 1 * time.Second},
+				{Key: "deprovision", Value: 90 * time.Second},
+			}))
+		})
+	})
+
+	Context("Export SSH access check results", func() {
+		It("should report the SSH session time", func() {
+			report := &PushReport{
+				AppName: "the-app-name",
+				SSH:     &SSHReport{SessionTime: 3 * time.Second, Passed: true},
+			}
+
+			export := report.Export()
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "ssh session", Value: 3 * time.Second}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "ssh passed", Value: true}))
+		})
+
+		It("should report a skipped SSH access check", func() {
+			report := &PushReport{
+				AppName: "the-app-name",
+				SSH:     &SSHReport{SkipReason: "disabled for the space"},
+			}
+
+			export := report.Export()
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "ssh", Value: "skipped, disabled for the space"}))
+		})
+	})
+
+	Context("Export one-off task results", func() {
+		It("should report the scheduling and execution time of the task", func() {
+			start := time.Now()
+			report := &PushReport{
+				AppName: "the-app-name",
+				Task: &TaskReport{
+					Name:         "gonut-task",
+					State:        "SUCCEEDED",
+					SubmitStart:  start,
+					RunningStart: start.Add(2 * time.Second),
+					End:          start.Add(6 * time.Second),
+				},
+			}
+
+			export := report.Export()
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "task scheduling", Value: 2 * time.Second}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "task execution", Value: 4 * time.Second}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "task state", Value: "SUCCEEDED"}))
+		})
+	})
+
+	Context("Export log streaming results", func() {
+		It("should report the log latency", func() {
+			report := &PushReport{
+				AppName: "the-app-name",
+				Logs:    &LogReport{Latency: 1500 * time.Millisecond},
+			}
+
+			Expect(report.Export()).To(ContainElement(yaml.MapItem{Key: "log latency", Value: 1500 * time.Millisecond}))
+		})
+
+		It("should report a lost log line", func() {
+			report := &PushReport{
+				AppName: "the-app-name",
+				Logs:    &LogReport{Lost: true},
+			}
+
+			Expect(report.Export()).To(ContainElement(yaml.MapItem{Key: "log latency", Value: "lost"}))
+		})
+	})
+
+	Context("Export container stats", func() {
+		It("should validate and report the container resource usage", func() {
+			data, err := ioutil.ReadFile("../../../assets/test/cf-curl/v2/apps/stats.json")
+			Expect(err).ToNot(HaveOccurred())
+
+			var stats AppStats
+			Expect(json.Unmarshal(data, &stats)).ToNot(HaveOccurred())
+
+			running := NewContainerStats(stats["0"])
+			Expect(running.Validate()).ToNot(HaveOccurred())
+
+			starting := NewContainerStats(stats["1"])
+			Expect(starting.Validate()).To(HaveOccurred())
+
+			report := &PushReport{AppName: "the-app-name", Stats: &running}
+			export := report.Export()
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "cpu usage", Value: "0.12%"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "memory usage", Value: "8.0 MiB of 128.0 MiB"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "disk usage", Value: "9.1 MiB of 128.0 MiB"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "uptime", Value: 42 * time.Second}))
+		})
+
+		It("should reject a memory usage above the quota", func() {
+			stats := ContainerStats{Memory: 256, MemoryQuota: 128, Disk: 64, DiskQuota: 128}
+			Expect(stats.Validate()).To(HaveOccurred())
+		})
+	})
+
+	Context("Export route registration latency", func() {
+		It("should report the time from the app running until the route answers", func() {
+			start := time.Now()
+			report := &PushReport{
+				AppName:            "the-app-name",
+				InitStart:          start,
+				PushEnd:            start.Add(30 * time.Second),
+				RouteRegisteredEnd: start.Add(33 * time.Second),
+				StatusCode:         200,
+			}
+
+			Expect(report.RouteRegistrationTime()).To(BeEquivalentTo(3 * time.Second))
+			Expect(report.Export()).To(ContainElement(yaml.MapItem{Key: "route registration", Value: 3 * time.Second}))
+		})
+
+		It("should not report a route registration if the app was not pinged", func() {
+			report := &PushReport{AppName: "the-app-name", PushEnd: time.Now()}
+
+			Expect(report.RouteRegistrationTime()).To(BeEquivalentTo(time.Duration(0)))
+			for _, item := range report.Export() {
+				Expect(item.Key).ToNot(BeEquivalentTo("route registration"))
+			}
+		})
+	})
+
+	Context("Export protocol check results", func() {
+		It("should report WebSocket and HTTP/2 results separately", func() {
+			report := &PushReport{
+				AppName: "the-app-name",
+				Protocols: &ProtocolReport{
+					WebSocket:     true,
+					WebSocketTime: 300 * time.Millisecond,
+					HTTP2:         true,
+					HTTP2Info:     "HTTP/2.0",
+					HTTP2App:      true,
+					HTTP2AppInfo:  "HTTP/2.0",
+				},
+			}
+
+			export := report.Export()
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "websocket", Value: true}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "websocket echo", Value: 300 * time.Millisecond}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "http/2 to router", Value: "HTTP/2.0"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "http/2 to app", Value: "HTTP/2.0"}))
+		})
+	})
+
+	Context("Export sample app runtime details", func() {
+		It("should report the runtime and memory limit of the app", func() {
+			report := &PushReport{
+				AppName: "the-app-name",
+				Runtime: &RuntimeInfo{
+					Language:       "go",
+					RuntimeVersion: "go1.12.9",
+					MemoryLimit:    "64m",
+				},
+			}
+
+			export := report.Export()
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "runtime", Value: "go go1.12.9"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "memory limit", Value: "64m"}))
+		})
+
+		It("should report the instance, stack, and application details the app sees", func() {
+			data, err := ioutil.ReadFile("../../../assets/test/runtime/golang.json")
+			Expect(err).ToNot(HaveOccurred())
+
+			var runtime RuntimeInfo
+			Expect(json.Unmarshal(data, &runtime)).ToNot(HaveOccurred())
+
+			export := (&PushReport{AppName: "the-app-name", Runtime: &runtime}).Export()
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "instance index", Value: "0"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "runtime stack", Value: "cflinuxfs3"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "application id", Value: "5b3a8f0e-7d6c-4b1a-9e2f-0c4d8a6b2e71"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "org and space", Value: "homeport/gonut"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "application uris", Value: "gonut-golang-app-x7k2p9.example.com"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "memory quota", Value: "64.0 MiB"}))
+			Expect(export).To(ContainElement(yaml.MapItem{Key: "disk quota", Value: "1.0 GiB"}))
+		})
+	})
 })
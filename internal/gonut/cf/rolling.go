@@ -0,0 +1,225 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gonvenience/wait"
+	"github.com/homeport/gonut/internal/gonut/nok"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// availabilityProbeInterval is the time between two requests of the
+// availability probe that runs during a rolling deployment
+const availabilityProbeInterval = 200 * time.Millisecond
+
+// availabilityProbeTimeout is the maximum time a single request of the
+// availability probe may take before it counts as failed
+const availabilityProbeTimeout = 5 * time.Second
+
+// RollingDeploymentReport encapsules details of a rolling redeployment of an
+// already pushed app and the availability of the app during it
+type RollingDeploymentReport struct {
+	LifecycleReport
+
+	Requests  int
+	Failures  int
+	Downtimes []DowntimeWindow
+}
+
+// DowntimeWindow is a period of time in which the app did not respond with
+// the statuscode 200
+type DowntimeWindow struct {
+	Start    time.Time
+	Duration time.Duration
+}
+
+type probeResult struct {
+	time time.Time
+	ok   bool
+}
+
+// TotalDowntime is the sum of all downtime windows
+func (report RollingDeploymentReport) TotalDowntime() time.Duration {
+	var total time.Duration
+	for _, downtime := range report.Downtimes {
+		total += downtime.Duration
+	}
+
+	return total
+}
+
+// Export creates a less technical representation of the report
+func (report *RollingDeploymentReport) Export() yaml.MapSlice {
+	result := report.LifecycleReport.Export()
+	result = append(result,
+		yaml.MapItem{Key: "requests", Value: report.Requests},
+		yaml.MapItem{Key: "failed requests", Value: report.Failures},
+		yaml.MapItem{Key: "downtime windows", Value: len(report.Downtimes)},
+	)
+
+	if len(report.Downtimes) > 0 {
+		result = append(result,
+			yaml.MapItem{Key: "total downtime", Value: report.TotalDowntime()},
+		)
+	}
+
+	return result
+}
+
+// ExportTable creates a less technical representation of the report in form of
+// a two-dimensional array
+func (report *RollingDeploymentReport) ExportTable() [][]string {
+	return exportTable(report.Export())
+}
+
+// runRollingDeployment pushes the app again using the rolling deployment
+// strategy while the app route is probed continuously in the background
+func runRollingDeployment(ctx context.Context, spinner *wait.ProgressIndicator, phases *phaseTracker, caption string, appName string, appRoute string, pushArgs []string) (*RollingDeploymentReport, error) {
+	report := &RollingDeploymentReport{
+		LifecycleReport: LifecycleReport{Operation: "rolling deployment"},
+	}
+
+	probeCtx, stopProbe := context.WithCancel(ctx)
+	defer stopProbe()
+
+	results := startAvailabilityProbe(probeCtx, appRoute)
+
+	args := append(append([]string{}, pushArgs...), "--strategy", "rolling")
+	output, err := runTrackedCommand(ctx, spinner, phases, caption, "Redeploying", &report.LifecycleReport, args...)
+
+	stopProbe()
+	report.evaluate(<-results)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		return report, nok.Errorf(
+			fmt.Sprintf("failed to redeploy application %s using the rolling strategy", appName),
+			output,
+		)
+	}
+
+	if report.Failures > 0 {
+		windows := make([]string, 0, len(report.Downtimes))
+		for _, downtime := range report.Downtimes {
+			windows = append(windows, fmt.Sprintf("%s for %s", downtime.Start.Format(time.RFC3339), downtime.Duration))
+		}
+
+		return report, nok.Errorf(
+			fmt.Sprintf("application %s was not available during the rolling deployment", appName),
+			"%d out of %d requests did not return the statuscode 200, downtime windows:\n%s",
+			report.Failures,
+			report.Requests,
+			strings.Join(windows, "\n"),
+		)
+	}
+
+	return report, nil
+}
+
+// startAvailabilityProbe sends requests to the app route until the context is
+// done, the results are sent through the returned channel afterwards
+func startAvailabilityProbe(ctx context.Context, appRoute string) <-chan []probeResult {
+	out := make(chan []probeResult, 1)
+
+	go func() {
+		var (
+			results []probeResult
+			lock    sync.Mutex
+			wg      sync.WaitGroup
+		)
+
+		ticker := time.NewTicker(availabilityProbeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				out <- results
+				return
+
+			case now := <-ticker.C:
+				wg.Add(1)
+				go func(now time.Time) {
+					defer wg.Done()
+
+					requestCtx, cancel := context.WithTimeout(context.Background(), availabilityProbeTimeout)
+					defer cancel()
+
+					statusCode, err := getAppStatusCode(requestCtx, appRoute)
+
+					lock.Lock()
+					results = append(results, probeResult{time: now, ok: err == nil && statusCode == http.StatusOK})
+					lock.Unlock()
+				}(now)
+			}
+		}
+	}()
+
+	return out
+}
+
+// evaluate counts the failed requests and determines the downtime windows
+func (report *RollingDeploymentReport) evaluate(results []probeResult) {
+	// Requests run concurrently, so results are not necessarily in order
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].time.Before(results[j].time)
+	})
+
+	report.Requests = len(results)
+
+	var windowStart *time.Time
+	for i := range results {
+		switch {
+		case !results[i].ok:
+			report.Failures++
+			if windowStart == nil {
+				windowStart = &results[i].time
+			}
+
+		case windowStart != nil:
+			report.Downtimes = append(report.Downtimes, DowntimeWindow{
+				Start:    *windowStart,
+				Duration: results[i].time.Sub(*windowStart),
+			})
+
+			windowStart = nil
+		}
+	}
+
+	if windowStart != nil {
+		report.Downtimes = append(report.Downtimes, DowntimeWindow{
+			Start:    *windowStart,
+			Duration: results[len(results)-1].time.Sub(*windowStart) + availabilityProbeInterval,
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/gonut/internal/gonut/cf"
+)
+
+var _ = Describe("Cloud Foundry rolling deployment report", func() {
+	Context("Parse Cloud Foundry rolling push output", func() {
+		It("should parse the staging and deployment phases", func() {
+			report := &RollingDeploymentReport{}
+			linefeeder("../../../assets/test/cf-lifecycle/rolling.log", func(text string) {
+				report.ParseUpdate(text)
+			})
+
+			Expect(report.StoppingStart).To(BeEquivalentTo(time.Time{}))
+			Expect(report.StagingStart).ToNot(BeEquivalentTo(time.Time{}))
+			Expect(report.StartingStart).ToNot(BeEquivalentTo(time.Time{}))
+		})
+	})
+
+	Context("Export rolling deployment reports", func() {
+		It("should list the availability of the app during the deployment", func() {
+			start := time.Now()
+			report := &RollingDeploymentReport{
+				LifecycleReport: LifecycleReport{
+					Operation:     "rolling deployment",
+					InitStart:     start,
+					StagingStart:  start.Add(2 * time.Second),
+					StartingStart: start.Add(20 * time.Second),
+					End:           start.Add(40 * time.Second),
+				},
+				Requests: 200,
+				Failures: 3,
+				Downtimes: []DowntimeWindow{
+					{Start: start.Add(30 * time.Second), Duration: 400 * time.Millisecond},
+					{Start: start.Add(35 * time.Second), Duration: 200 * time.Millisecond},
+				},
+			}
+
+			Expect(report.TotalDowntime()).To(BeEquivalentTo(600 * time.Millisecond))
+
+			export := report.Export()
+			Expect(export[0].Value).To(BeEquivalentTo(40 * time.Second))
+			Expect(export[len(export)-4].Key).To(BeEquivalentTo("requests"))
+			Expect(export[len(export)-4].Value).To(BeEquivalentTo(200))
+			Expect(export[len(export)-3].Key).To(BeEquivalentTo("failed requests"))
+			Expect(export[len(export)-3].Value).To(BeEquivalentTo(3))
+			Expect(export[len(export)-2].Key).To(BeEquivalentTo("downtime windows"))
+			Expect(export[len(export)-2].Value).To(BeEquivalentTo(2))
+			Expect(export[len(export)-1].Key).To(BeEquivalentTo("total downtime"))
+			Expect(export[len(export)-1].Value).To(BeEquivalentTo(600 * time.Millisecond))
+		})
+
+		It("should not list a total downtime if the app was always available", func() {
+			report := &RollingDeploymentReport{Requests: 100}
+
+			export := report.Export()
+			Expect(export[len(export)-1].Key).To(BeEquivalentTo("downtime windows"))
+			Expect(export[len(export)-1].Value).To(BeEquivalentTo(0))
+		})
+	})
+})
@@ -0,0 +1,106 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+)
+
+// routeRegistrationTimeout is the maximum time the route of a running app may
+// take until it answers through the router
+const routeRegistrationTimeout = time.Minute
+
+// routeRegistrationPollInterval is the time to wait between two requests to
+// the app route while waiting for the route registration
+const routeRegistrationPollInterval = 250 * time.Millisecond
+
+// routeRegistrationRequestTimeout is the maximum time a single request to the
+// app route may take, so that an unresponsive route does not block the polling
+const routeRegistrationRequestTimeout = 5 * time.Second
+
+// PushedAppRoute returns the URL of the first route listed in the output of
+// the push command, so that the route can be polled right after the app is
+// running without looking it up first, the second return value is false if the
+// output does not list any route
+func PushedAppRoute(output string) (string, bool) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		// Older versions of the CF CLI list the routes as urls
+		switch fields[0] {
+		case "routes:", "urls:":
+			route := strings.TrimSuffix(fields[1], ",")
+			return fmt.Sprintf("http://%s", route), true
+		}
+	}
+
+	return "", false
+}
+
+// waitForRouteRegistration polls the app route until it answers with the
+// statuscode 200 and returns the last statuscode
+func waitForRouteRegistration(ctx context.Context, appName string, appRoute string) (int, error) {
+	var (
+		statusCode int
+		err        error
+		start      = time.Now()
+	)
+
+	for {
+		requestCtx, cancel := context.WithTimeout(ctx, routeRegistrationRequestTimeout)
+		statusCode, err = getAppStatusCode(requestCtx, appRoute)
+		cancel()
+
+		if err == nil && statusCode == http.StatusOK {
+			return statusCode, nil
+		}
+
+		if time.Since(start) > routeRegistrationTimeout {
+			if err != nil {
+				return statusCode, nok.Errorf(
+					fmt.Sprintf("unable to ping application %s with route %s", appName, appRoute),
+					err.Error(),
+				)
+			}
+
+			return statusCode, nok.Errorf(
+				fmt.Sprintf("application %s returned a non-ok statuscode %d", appName, statusCode),
+				"The application did not return the statuscode 200 within %s. Please try to push the same sample application again.", routeRegistrationTimeout,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return statusCode, ctx.Err()
+
+		case <-time.After(routeRegistrationPollInterval):
+		}
+	}
+}
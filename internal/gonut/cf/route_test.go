@@ -0,0 +1,58 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/gonut/internal/gonut/cf"
+)
+
+var _ = Describe("Cloud Foundry app route", func() {
+	Context("Parse the route from the push output", func() {
+		It("should return the first route listed by the push command", func() {
+			output := `Waiting for app to start...
+
+name:              gonut-golang-app
+requested state:   started
+routes:            gonut-golang-app.example.com, gonut-golang-app.example.org
+last uploaded:     Mon 19 Oct 10:42:17 UTC 2026
+stack:             cflinuxfs3
+`
+
+			route, ok := PushedAppRoute(output)
+			Expect(ok).To(BeTrue())
+			Expect(route).To(BeEquivalentTo("http://gonut-golang-app.example.com"))
+		})
+
+		It("should support the urls line of older CF CLI versions", func() {
+			route, ok := PushedAppRoute("requested state: started\nurls: gonut-golang-app.example.com\n")
+			Expect(ok).To(BeTrue())
+			Expect(route).To(BeEquivalentTo("http://gonut-golang-app.example.com"))
+		})
+
+		It("should report when the app has no route", func() {
+			_, ok := PushedAppRoute("name:              gonut-golang-app\nroutes:\n")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
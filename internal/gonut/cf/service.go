@@ -0,0 +1,307 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gonvenience/wait"
+	"github.com/homeport/gonut/internal/gonut/nok"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// serviceOperationPollInterval is the time to wait between two checks whether
+// an asynchronous service broker operation is finished
+const serviceOperationPollInterval = 2 * time.Second
+
+// serviceOperationTimeout is the maximum time a service broker may take to
+// provision or deprovision a service instance
+const serviceOperationTimeout = 10 * time.Minute
+
+// ServiceReport encapsules details of the service binding check, in which a
+// marketplace service is created and bound to the pushed app
+type ServiceReport struct {
+	Offering     string
+	Plan         string
+	InstanceName string
+
+	ProvisionTime   time.Duration
+	BindTime        time.Duration
+	RestartTime     time.Duration
+	UnbindTime      time.Duration
+	DeprovisionTime time.Duration
+}
+
+// Export creates a less technical representation of the report
+func (report *ServiceReport) Export() yaml.MapSlice {
+	result := yaml.MapSlice{
+		yaml.MapItem{Key: "offering", Value: report.Offering},
+		yaml.MapItem{Key: "plan", Value: report.Plan},
+	}
+
+	for _, item := range []yaml.MapItem{
+		{Key: "provision", Value: report.ProvisionTime},
+		{Key: "bind", Value: report.BindTime},
+		{Key: "restart", Value: report.RestartTime},
+		{Key: "unbind", Value: report.UnbindTime},
+		{Key: "deprovision", Value: report.DeprovisionTime},
+	} {
+		if item.Value.(time.Duration) > 0 {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// ExportTable creates a less technical representation of the report in form of
+// a two-dimensional array
+func (report *ServiceReport) ExportTable() [][]string {
+	return exportTable(report.Export())
+}
+
+// runServiceBindingCheck creates a service instance of the given offering and
+// plan, binds it to the app, and verifies that the app sees the credentials in
+// VCAP_SERVICES, the binding and the instance are deleted afterwards
+func runServiceBindingCheck(ctx context.Context, spinner *wait.ProgressIndicator, phases *phaseTracker, caption string, appName string, appRoute string, settings PushSettings) (report *ServiceReport, err error) {
+	report = &ServiceReport{
+		Offering:     settings.ServiceOffering,
+		Plan:         settings.ServicePlan,
+		InstanceName: fmt.Sprintf("%s-service", appName),
+	}
+
+	var (
+		created bool
+		bound   bool
+	)
+
+	// Remove what is left in case the check did not finish, the app itself is
+	// deleted afterwards by the push cleanup
+	if settings.CleanupSetting == Always {
+		defer func() {
+			if !created {
+				return
+			}
+
+			cleanupCtx, cancel := CleanupContext(settings.CleanupTimeout)
+			defer cancel()
+
+			if bound {
+				cf(cleanupCtx, nil, "unbind-service", appName, report.InstanceName)
+			}
+
+			cf(cleanupCtx, nil, "delete-service", report.InstanceName, "-f")
+		}()
+	}
+
+	step := func(name string) {
+		phases.enter(name)
+		spinner.SetText("*%s*, DimGray{%s} - %s %s", caption, name, report.Offering, report.Plan)
+	}
+
+	// Provision the service instance and wait for the broker to finish
+	step("Provisioning")
+	start := time.Now()
+	if output, err := cf(ctx, nil, "create-service", report.Offering, report.Plan, report.InstanceName); err != nil {
+		return report, operationError(ctx, fmt.Sprintf("failed to create service instance of %s with plan %s", report.Offering, report.Plan), output)
+	}
+	created = true
+
+	instanceGUID, err := cfServiceInstanceGUID(ctx, report.InstanceName)
+	if err != nil {
+		return report, operationError(ctx, fmt.Sprintf("failed to look up service instance %s", report.InstanceName), err.Error())
+	}
+
+	if err := waitForServiceOperation(ctx, report.InstanceName, instanceGUID); err != nil {
+		return report, err
+	}
+	report.ProvisionTime = time.Since(start)
+
+	// Bind the service instance and restart the app so that it picks it up
+	step("Binding")
+	start = time.Now()
+	if output, err := cf(ctx, nil, "bind-service", appName, report.InstanceName); err != nil {
+		return report, operationError(ctx, fmt.Sprintf("failed to bind service instance %s to application %s", report.InstanceName, appName), output)
+	}
+	bound = true
+	report.BindTime = time.Since(start)
+
+	step("Restarting")
+	start = time.Now()
+	if output, err := cf(ctx, nil, "restart", appName); err != nil {
+		return report, operationError(ctx, fmt.Sprintf("failed to restart application %s after binding the service", appName), output)
+	}
+	report.RestartTime = time.Since(start)
+
+	step("Verifying")
+	if err := verifyServiceCredentials(ctx, appName, appRoute, report.InstanceName); err != nil {
+		return report, err
+	}
+
+	// Remove the binding and the service instance again
+	step("Unbinding")
+	start = time.Now()
+	if output, err := cf(ctx, nil, "unbind-service", appName, report.InstanceName); err != nil {
+		return report, operationError(ctx, fmt.Sprintf("failed to unbind service instance %s from application %s", report.InstanceName, appName), output)
+	}
+	bound = false
+	report.UnbindTime = time.Since(start)
+
+	step("Deprovisioning")
+	start = time.Now()
+	if output, err := cf(ctx, nil, "delete-service", report.InstanceName, "-f"); err != nil {
+		return report, operationError(ctx, fmt.Sprintf("failed to delete service instance %s", report.InstanceName), output)
+	}
+
+	if err := waitForServiceOperation(ctx, report.InstanceName, instanceGUID); err != nil {
+		return report, err
+	}
+	created = false
+	report.DeprovisionTime = time.Since(start)
+
+	phases.enter("Verifying")
+	return report, nil
+}
+
+// waitForServiceOperation polls the service instance until the last operation
+// of the broker succeeded, or until the instance is gone after a delete
+func waitForServiceOperation(ctx context.Context, instanceName string, instanceGUID string) error {
+	deadline := time.Now().Add(serviceOperationTimeout)
+
+	for {
+		details, err := cfCurlServiceInstance(ctx, instanceGUID)
+		if err == nil {
+			// A deleted service instance is reported with an error and no guid
+			if details.Metadata.GUID == "" {
+				return nil
+			}
+
+			switch details.Entity.LastOperation.State {
+			case "succeeded":
+				if details.Entity.LastOperation.Type != "delete" {
+					return nil
+				}
+
+			case "failed":
+				return nok.Errorf(
+					fmt.Sprintf("service broker failed to %s service instance %s", details.Entity.LastOperation.Type, instanceName),
+					details.Entity.LastOperation.Description,
+				)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nok.Errorf(
+				fmt.Sprintf("service instance %s was not ready in time", instanceName),
+				"The service broker operation did not finish within %s.", serviceOperationTimeout,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-time.After(serviceOperationPollInterval):
+		}
+	}
+}
+
+// verifyServiceCredentials requests the services endpoint of the sample app and
+// checks that the app reports credentials for the service instance
+func verifyServiceCredentials(ctx context.Context, appName string, appRoute string, instanceName string) error {
+	caption := fmt.Sprintf("application %s did not receive the credentials of service instance %s", appName, instanceName)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/services", appRoute), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return operationError(ctx, caption, err.Error())
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return operationError(ctx, caption, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nok.Errorf(caption, "The services endpoint returned the statuscode %d.", resp.StatusCode)
+	}
+
+	// The app only lists the names of the bound service instances and whether
+	// credentials are present, so that no credentials are published
+	var services []struct {
+		Name        string `json:"name"`
+		Credentials bool   `json:"credentials"`
+	}
+
+	if err := json.Unmarshal(data, &services); err != nil {
+		return nok.Errorf(caption, "The services endpoint returned no valid list of service instances: %v", err)
+	}
+
+	for _, instance := range services {
+		if instance.Name == instanceName && instance.Credentials {
+			return nil
+		}
+	}
+
+	return nok.Errorf(caption, "The app reports no credentials for the service instance, bound services are:\n%s", string(data))
+}
+
+func operationError(ctx context.Context, caption string, details string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return nok.Errorf(caption, details)
+}
+
+func cfServiceInstanceGUID(ctx context.Context, instanceName string) (string, error) {
+	result, err := cf(ctx, nil, "service", instanceName, "--guid")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Trim(result, " \n"), nil
+}
+
+func cfCurlServiceInstance(ctx context.Context, instanceGUID string) (*ServiceInstanceDetails, error) {
+	result, err := cf(ctx, nil, "curl", fmt.Sprintf("/v2/service_instances/%s", instanceGUID))
+	if err != nil {
+		return nil, err
+	}
+
+	var details ServiceInstanceDetails
+	if err := json.Unmarshal([]byte(result), &details); err != nil {
+		return nil, err
+	}
+
+	return &details, nil
+}
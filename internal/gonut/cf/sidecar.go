@@ -0,0 +1,294 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// sidecarTimeout is the maximum time the sidecar may take to be reachable
+const sidecarTimeout = time.Minute
+
+// sidecarPollInterval is the time to wait between two sidecar requests
+const sidecarPollInterval = time.Second
+
+// SidecarDefinition is a sidecar as declared in the sidecars section of an
+// app manifest
+type SidecarDefinition struct {
+	Name         string   `yaml:"name" json:"name"`
+	Command      string   `yaml:"command" json:"command"`
+	ProcessTypes []string `yaml:"process_types" json:"process_types"`
+}
+
+// SidecarReport contains the outcome of the sidecar verification
+type SidecarReport struct {
+	Names          []string
+	MainRunning    bool
+	SidecarRunning bool
+	Reachable      bool
+	ReachTime      time.Duration
+}
+
+// ExtractManifestSidecars removes the sidecars section of the first
+// application in the manifest at the given path and returns the sidecars. The
+// sidecars are created using the API afterwards, because not all CF CLI
+// versions support sidecars in the manifest.
+func ExtractManifestSidecars(path string) ([]SidecarDefinition, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var manifest yaml.MapSlice
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+
+	applications, ok := lookUpValue(manifest, "applications").([]interface{})
+	if !ok || len(applications) == 0 {
+		return nil, fmt.Errorf("manifest %s does not contain any application", path)
+	}
+
+	app, ok := applications[0].(yaml.MapSlice)
+	if !ok {
+		return nil, fmt.Errorf("manifest %s contains an unsupported application definition", path)
+	}
+
+	section := lookUpValue(app, "sidecars")
+	if section == nil {
+		return nil, nil
+	}
+
+	tmp, err := yaml.Marshal(section)
+	if err != nil {
+		return nil, err
+	}
+
+	var sidecars []SidecarDefinition
+	if err := yaml.UnmarshalStrict(tmp, &sidecars); err != nil {
+		return nil, fmt.Errorf("manifest %s contains an unsupported sidecar definition: %v", path, err)
+	}
+
+	applications[0] = removeValue(app, "sidecars")
+
+	out, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return sidecars, ioutil.WriteFile(path, out, os.FileMode(0644))
+}
+
+// verifySidecars creates the sidecars of the app, restarts the app so that
+// the sidecars are started, and verifies that the main process is running,
+// that the sidecar processes show up in the process list of the container,
+// and that the main process can reach the sidecar over localhost
+func verifySidecars(ctx context.Context, appName string, appRoute string, sidecars []SidecarDefinition) (*SidecarReport, error) {
+	report := &SidecarReport{}
+
+	appGUID, err := cfAppGUID(ctx, appName)
+	if err != nil {
+		return report, err
+	}
+
+	// The sidecars were removed from the manifest before the push, so all of
+	// them are created using the API
+	for _, sidecar := range sidecars {
+		report.Names = append(report.Names, sidecar.Name)
+
+		if err := cfCurlCreateSidecar(ctx, appGUID, sidecar); err != nil {
+			return report, operationError(ctx,
+				fmt.Sprintf("failed to create sidecar %s of application %s", sidecar.Name, appName),
+				err.Error(),
+			)
+		}
+	}
+
+	// Sidecars only start with the next start of the app
+	if output, err := cf(ctx, nil, "restart", appName); err != nil {
+		return report, operationError(ctx,
+			fmt.Sprintf("failed to restart application %s with sidecars", appName),
+			output,
+		)
+	}
+
+	if stats, err := cfCurlAppStats(ctx, appGUID); err == nil {
+		instance, ok := stats["0"]
+		report.MainRunning = ok && instance.State == "RUNNING"
+	}
+
+	if !report.MainRunning {
+		return report, nok.Errorf(
+			fmt.Sprintf("main process of application %s is not running", appName),
+			"The main process is expected to run next to the sidecars %s.", strings.Join(report.Names, ", "),
+		)
+	}
+
+	start := time.Now()
+	for {
+		// Sidecars share the container with the main process and are no
+		// separate processes in the API, so the app lists the processes of
+		// its container to show that the sidecar commands are running
+		if !report.SidecarRunning {
+			if commands, err := getContainerCommands(ctx, appRoute); err == nil {
+				report.SidecarRunning = sidecarsRunning(commands, sidecars)
+			}
+		}
+
+		if !report.Reachable {
+			if err := reachSidecar(ctx, appRoute); err == nil {
+				report.Reachable = true
+				report.ReachTime = time.Since(start)
+			}
+		}
+
+		if report.SidecarRunning && report.Reachable {
+			return report, nil
+		}
+
+		if time.Since(start) > sidecarTimeout {
+			if !report.SidecarRunning {
+				return report, nok.Errorf(
+					fmt.Sprintf("sidecar process of application %s is not running", appName),
+					"The process list of the app container did not contain the sidecars %s within %s.", strings.Join(report.Names, ", "), sidecarTimeout,
+				)
+			}
+
+			return report, nok.Errorf(
+				fmt.Sprintf("sidecar of application %s is not reachable", appName),
+				"The app was not able to reach its sidecar over localhost within %s.", sidecarTimeout,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+
+		case <-time.After(sidecarPollInterval):
+		}
+	}
+}
+
+// sidecarsRunning returns whether the command of each sidecar is part of the
+// given command lines of the processes in the app container
+func sidecarsRunning(commands []string, sidecars []SidecarDefinition) bool {
+	for _, sidecar := range sidecars {
+		running := false
+		for _, command := range commands {
+			if strings.Contains(command, sidecar.Command) {
+				running = true
+				break
+			}
+		}
+
+		if !running {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getContainerCommands requests the processes endpoint of the sample app,
+// which lists the command lines of all processes in the app container
+func getContainerCommands(ctx context.Context, appRoute string) ([]string, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/processes", appRoute), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("processes endpoint returned statuscode %d", resp.StatusCode)
+	}
+
+	var commands []string
+	if err := json.NewDecoder(resp.Body).Decode(&commands); err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
+
+// reachSidecar requests the sidecar endpoint of the sample app, which
+// forwards the request to the sidecar over localhost
+func reachSidecar(ctx context.Context, appRoute string) error {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/sidecar", appRoute), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sidecar endpoint returned statuscode %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func cfCurlCreateSidecar(ctx context.Context, appGUID string, sidecar SidecarDefinition) error {
+	data, err := json.Marshal(sidecar)
+	if err != nil {
+		return err
+	}
+
+	result, err := cf(ctx, nil, "curl", "-X", "POST", fmt.Sprintf("/v3/apps/%s/sidecars", appGUID), "-d", string(data))
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, result)
+	}
+
+	// Errors of the Cloud Controller are returned with a successful exit code
+	var response struct {
+		Errors []struct {
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+
+	if err := json.Unmarshal([]byte(result), &response); err == nil && len(response.Errors) > 0 {
+		return fmt.Errorf("%s", response.Errors[0].Detail)
+	}
+
+	return nil
+}
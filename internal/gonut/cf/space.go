@@ -0,0 +1,235 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+)
+
+// EphemeralSpace is a temporary space that is created for an isolated gonut
+// run and that is deleted including all of its content afterwards
+type EphemeralSpace struct {
+	Org   string
+	Space string
+	Quota string
+
+	previousOrg   string
+	previousSpace string
+}
+
+// accessTokenClaims contains the parts of the UAA access token payload that are
+// required to check the user permissions
+type accessTokenClaims struct {
+	UserID string   `json:"user_id"`
+	Scope  []string `json:"scope"`
+}
+
+// CreateEphemeralSpace creates a space with the given name in the given org
+// (or the currently targeted org if empty) and targets it. If a quota name is
+// provided, the space quota is assigned to the new space. If the space cannot
+// be targeted, it is deleted again within the given cleanup timeout.
+func CreateEphemeralSpace(ctx context.Context, org string, space string, quota string, cleanupTimeout time.Duration) (*EphemeralSpace, error) {
+	caption := fmt.Sprintf("failed to create ephemeral space %s", space)
+
+	if !isLoggedIn() {
+		return nil, nok.Errorf(caption, "session is not logged into a Cloud Foundry environment")
+	}
+
+	previousOrg, previousSpace, err := getOrgAndSpaceNamesFromConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(org) == 0 {
+		org = previousOrg
+	}
+
+	if len(org) == 0 {
+		return nil, nok.Errorf(caption, "no org is configured and no org is targeted")
+	}
+
+	if err := checkSpaceCreationPermission(ctx, org); err != nil {
+		return nil, err
+	}
+
+	args := []string{"create-space", space, "-o", org}
+	if len(quota) > 0 {
+		args = append(args, "-q", quota)
+	}
+
+	result := &EphemeralSpace{
+		Org:           org,
+		Space:         space,
+		Quota:         quota,
+		previousOrg:   previousOrg,
+		previousSpace: previousSpace,
+	}
+
+	// In case gonut is interrupted while the space is created, the space
+	// might exist already even though the CF CLI call did not finish
+	if output, err := cf(ctx, nil, args...); err != nil {
+		if ctx.Err() != nil {
+			return nil, result.deleteAfterFailure(caption, output, cleanupTimeout)
+		}
+
+		return nil, nok.Errorf(caption, output)
+	}
+
+	if output, err := cf(ctx, nil, "target", "-o", org, "-s", space); err != nil {
+		return nil, result.deleteAfterFailure(caption, output, cleanupTimeout)
+	}
+
+	return result, nil
+}
+
+// deleteAfterFailure deletes the space after its creation failed and returns
+// the creation error including the deletion error if there is one. The push
+// context might be cancelled already in case gonut was interrupted, therefore
+// the space is deleted using a cleanup context.
+func (s *EphemeralSpace) deleteAfterFailure(caption string, output string, cleanupTimeout time.Duration) error {
+	ctx, cancel := CleanupContext(cleanupTimeout)
+	defer cancel()
+
+	if err := s.Delete(ctx); err != nil {
+		return nok.Errorf(caption, "%s\n\nThe space could not be deleted again: %v", output, err)
+	}
+
+	return nok.Errorf(caption, output)
+}
+
+// Delete removes the ephemeral space including all apps, routes and service
+// instances in it and targets the previously targeted org and space again
+func (s *EphemeralSpace) Delete(ctx context.Context) error {
+	if output, err := cf(ctx, nil, "delete-space", s.Space, "-o", s.Org, "-f"); err != nil {
+		return nok.Errorf(
+			fmt.Sprintf("failed to delete ephemeral space %s", s.Space),
+			output,
+		)
+	}
+
+	if len(s.previousOrg) > 0 && len(s.previousSpace) > 0 {
+		if output, err := cf(ctx, nil, "target", "-o", s.previousOrg, "-s", s.previousSpace); err != nil {
+			return nok.Errorf(
+				fmt.Sprintf("failed to target previous space %s", s.previousSpace),
+				output,
+			)
+		}
+	}
+
+	return nil
+}
+
+// checkSpaceCreationPermission verifies that the current user is either a
+// Cloud Foundry admin or a manager of the given org, which is required to
+// create and delete spaces
+func checkSpaceCreationPermission(ctx context.Context, org string) error {
+	caption := fmt.Sprintf("insufficient permissions to create spaces in org %s", org)
+
+	claims, err := getAccessTokenClaims()
+	if err != nil {
+		return nok.Errorf(caption, "Unable to read the access token details: %v", err)
+	}
+
+	for _, scope := range claims.Scope {
+		if scope == "cloud_controller.admin" {
+			return nil
+		}
+	}
+
+	orgGUID, err := cf(ctx, nil, "org", org, "--guid")
+	if err != nil {
+		return nok.Errorf(caption, orgGUID)
+	}
+
+	managedOrgs, err := cfCurlManagedOrganizations(ctx, claims.UserID)
+	if err != nil {
+		return nok.Errorf(caption, "Unable to look up the organizations managed by the current user: %v", err)
+	}
+
+	for _, guid := range managedOrgs {
+		if guid == strings.TrimSpace(orgGUID) {
+			return nil
+		}
+	}
+
+	return nok.Errorf(caption, "An ephemeral space requires the current user to be a Cloud Foundry admin or an org manager of %s.", org)
+}
+
+func getAccessTokenClaims() (*accessTokenClaims, error) {
+	config, err := getCloudFoundryConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	token := strings.TrimSpace(config.AccessToken)
+	if idx := strings.Index(token, " "); idx >= 0 {
+		token = token[idx+1:]
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("access token is not a valid JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, err
+	}
+
+	var claims accessTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+func cfCurlManagedOrganizations(ctx context.Context, userGUID string) ([]string, error) {
+	result := []string{}
+	nextURL := fmt.Sprintf("/v2/users/%s/managed_organizations", userGUID)
+
+	for len(nextURL) > 0 {
+		output, err := cf(ctx, nil, "curl", nextURL)
+		if err != nil {
+			return nil, err
+		}
+
+		var page OrgsPage
+		if err := json.Unmarshal([]byte(output), &page); err != nil {
+			return nil, err
+		}
+
+		for _, org := range page.Resources {
+			result = append(result, org.Metadata.GUID)
+		}
+
+		nextURL = page.NextURL
+	}
+
+	return result, nil
+}
@@ -0,0 +1,97 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+)
+
+// SSHReport contains the outcome of the SSH access check of a pushed app
+type SSHReport struct {
+	SessionTime time.Duration
+	Passed      bool
+
+	// SkipReason is set if SSH is disabled and the check did not run
+	SkipReason string
+}
+
+// Skipped returns whether the SSH access check did not run
+func (report SSHReport) Skipped() bool {
+	return len(report.SkipReason) > 0
+}
+
+// verifySSHAccess runs a trivial command in the app container using cf ssh
+// and measures the time it takes to establish the session, the check is
+// skipped if SSH is disabled for the targeted space or for the app
+func verifySSHAccess(ctx context.Context, appName string) (*SSHReport, error) {
+	config, err := getCloudFoundryConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.SpaceFields.AllowSSH {
+		return &SSHReport{SkipReason: "disabled for the space"}, nil
+	}
+
+	app, err := getApp(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !app.Entity.EnableSSH {
+		return &SSHReport{SkipReason: "disabled for the app"}, nil
+	}
+
+	report := &SSHReport{}
+
+	// The command output has to contain the marker to prove that it ran
+	marker := fmt.Sprintf("gonut-ssh-%s", appName)
+
+	start := time.Now()
+	output, err := cf(ctx, nil, "ssh", appName, "-c", fmt.Sprintf("echo %s", marker))
+	report.SessionTime = time.Since(start)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		return report, nok.Errorf(
+			fmt.Sprintf("failed to run a command in application %s using SSH", appName),
+			output,
+		)
+	}
+
+	if !strings.Contains(output, marker) {
+		return report, nok.Errorf(
+			fmt.Sprintf("unexpected SSH command output of application %s", appName),
+			"The output of the command run using SSH does not contain the expected text %s:\n%s", marker, output,
+		)
+	}
+
+	report.Passed = true
+	return report, nil
+}
@@ -0,0 +1,127 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+)
+
+// containerStatsTimeout is the maximum time to wait for the metrics of the app
+// container to show sensible values
+const containerStatsTimeout = time.Minute
+
+// containerStatsPollInterval is the time to wait between two stats requests
+const containerStatsPollInterval = 2 * time.Second
+
+// ContainerStats contains the resource usage of an app instance container
+type ContainerStats struct {
+	CPU         float64
+	Memory      int64
+	MemoryQuota int64
+	Disk        int64
+	DiskQuota   int64
+	Uptime      time.Duration
+}
+
+// NewContainerStats creates the container stats from the instance stats as
+// returned by the Cloud Controller
+func NewContainerStats(stats InstanceStats) ContainerStats {
+	return ContainerStats{
+		CPU:         stats.Stats.Usage.CPU,
+		Memory:      stats.Stats.Usage.Mem,
+		MemoryQuota: stats.Stats.MemQuota,
+		Disk:        stats.Stats.Usage.Disk,
+		DiskQuota:   stats.Stats.DiskQuota,
+		Uptime:      time.Duration(stats.Stats.Uptime) * time.Second,
+	}
+}
+
+// Validate returns an error if the container stats do not contain sensible
+// values, for example no memory usage at all or a usage above the quota
+func (stats ContainerStats) Validate() error {
+	switch {
+	case stats.CPU < 0:
+		return fmt.Errorf("negative CPU usage %f", stats.CPU)
+
+	case stats.Memory <= 0:
+		return fmt.Errorf("no memory usage reported")
+
+	case stats.MemoryQuota > 0 && stats.Memory > stats.MemoryQuota:
+		return fmt.Errorf("memory usage %s exceeds the quota of %s", HumanReadableSize(stats.Memory), HumanReadableSize(stats.MemoryQuota))
+
+	case stats.Disk <= 0:
+		return fmt.Errorf("no disk usage reported")
+
+	case stats.DiskQuota > 0 && stats.Disk > stats.DiskQuota:
+		return fmt.Errorf("disk usage %s exceeds the quota of %s", HumanReadableSize(stats.Disk), HumanReadableSize(stats.DiskQuota))
+
+	case stats.Uptime < 0:
+		return fmt.Errorf("negative uptime %s", stats.Uptime)
+	}
+
+	return nil
+}
+
+// collectContainerStats polls the stats of the first app instance until the
+// metrics pipeline reports sensible values for it
+func collectContainerStats(ctx context.Context, appName string) (*ContainerStats, error) {
+	appGUID, err := cfAppGUID(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result *ContainerStats
+		reason = fmt.Errorf("no stats reported for the first instance")
+		start  = time.Now()
+	)
+
+	for {
+		if stats, err := cfCurlAppStats(ctx, appGUID); err == nil {
+			if instance, ok := stats["0"]; ok && instance.State == "RUNNING" {
+				containerStats := NewContainerStats(instance)
+				result = &containerStats
+
+				if reason = containerStats.Validate(); reason == nil {
+					return result, nil
+				}
+			}
+		}
+
+		if time.Since(start) > containerStatsTimeout {
+			return result, nok.Errorf(
+				fmt.Sprintf("container stats of application %s are not sensible", appName),
+				"The stats of the app container did not show sensible values within %s: %v", containerStatsTimeout, reason,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+
+		case <-time.After(containerStatsPollInterval):
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/homeport/gonut/internal/gonut/nok"
+)
+
+// taskCommand is the command of the one-off task, it takes a few seconds so
+// that the running state of the task can be observed
+const taskCommand = "echo gonut task && sleep 3"
+
+// taskPollInterval is the time to wait between two checks of the task state
+const taskPollInterval = 500 * time.Millisecond
+
+// taskTimeout is the maximum time a task may take until it succeeds or fails
+const taskTimeout = 5 * time.Minute
+
+// TaskReport contains the outcome of a one-off task run on the app droplet
+type TaskReport struct {
+	Name  string
+	State string
+
+	SubmitStart  time.Time
+	RunningStart time.Time
+	End          time.Time
+}
+
+// SchedulingTime is the time it takes from submitting the task until it runs
+func (report TaskReport) SchedulingTime() time.Duration {
+	return phaseDuration(report.SubmitStart, report.RunningStart, report.End)
+}
+
+// ExecutionTime is the time the task is running
+func (report TaskReport) ExecutionTime() time.Duration {
+	return phaseDuration(report.RunningStart, report.End)
+}
+
+// runTask runs a one-off task on the droplet of the app and polls the task
+// state until it succeeds or fails
+func runTask(ctx context.Context, appName string) (*TaskReport, error) {
+	report := &TaskReport{
+		Name: fmt.Sprintf("gonut-task-%d", time.Now().Unix()),
+	}
+
+	appGUID, err := cfAppGUID(ctx, appName)
+	if err != nil {
+		return report, err
+	}
+
+	report.SubmitStart = time.Now()
+	if output, err := cf(ctx, nil, "run-task", appName, taskCommand, "--name", report.Name); err != nil {
+		return report, operationError(ctx,
+			fmt.Sprintf("failed to run task on application %s", appName),
+			output,
+		)
+	}
+
+	for {
+		if task, err := cfCurlAppTask(ctx, appGUID, report.Name); err == nil && task != nil {
+			report.State = task.State
+
+			switch task.State {
+			case "RUNNING":
+				if report.RunningStart.IsZero() {
+					report.RunningStart = time.Now()
+				}
+
+			case "SUCCEEDED":
+				report.End = time.Now()
+				return report, nil
+
+			case "FAILED":
+				report.End = time.Now()
+
+				reason := "unknown reason"
+				if task.Result.FailureReason != nil {
+					reason = *task.Result.FailureReason
+				}
+
+				return report, nok.Errorf(
+					fmt.Sprintf("task %s of application %s failed", report.Name, appName),
+					reason,
+				)
+			}
+		}
+
+		if time.Since(report.SubmitStart) > taskTimeout {
+			return report, nok.Errorf(
+				fmt.Sprintf("task %s of application %s did not finish in time", report.Name, appName),
+				"The task was in state %s after %s.", report.State, taskTimeout,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
+
+func cfCurlAppTask(ctx context.Context, appGUID string, taskName string) (*TaskDetails, error) {
+	result, err := cf(ctx, nil, "curl", fmt.Sprintf("/v3/apps/%s/tasks?names=%s", appGUID, url.QueryEscape(taskName)))
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks TasksPage
+	if err := json.Unmarshal([]byte(result), &tasks); err != nil {
+		return nil, err
+	}
+
+	if len(tasks.Resources) == 0 {
+		return nil, nil
+	}
+
+	return &tasks.Resources[0], nil
+}
@@ -0,0 +1,161 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gonvenience/bunt"
+	"github.com/gonvenience/neat"
+	"github.com/gonvenience/text"
+	"github.com/homeport/gonut/internal/gonut/cf"
+	"github.com/spf13/cobra"
+)
+
+// c2cCmd represents the container-to-container networking scenario
+var c2cCmd = &cobra.Command{
+	Use:   "c2c",
+	Short: "Verify container-to-container networking in Cloud Foundry",
+	Long: fmt.Sprintf(`Push two Golang sample apps to Cloud Foundry, map an internal route (%s) to one of them, and add a network policy so that the other app can reach it through the container network. The time it takes until the network policy is in effect is reported. The applications and the network policy will be deleted afterwards.`,
+		cf.InternalDomain,
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runContainerNetworkingChecks(); err != nil {
+			ExitGonut(err)
+		}
+	},
+}
+
+func init() {
+	pushCmd.AddCommand(c2cCmd)
+}
+
+func runContainerNetworkingChecks() error {
+	if len(foundationSetting) == 0 {
+		return withEphemeralSpaceIfEnabled(runContainerNetworkingCheck)
+	}
+
+	foundations, err := loadSelectedFoundations()
+	if err != nil {
+		return err
+	}
+
+	for _, foundation := range foundations {
+		bunt.Printf("Using foundation *%s* (CadetBlue{%s})\n", foundation.Name, foundation.API)
+
+		if err := cf.WithFoundation(rootContext, foundation, func() error {
+			return withEphemeralSpaceIfEnabled(runContainerNetworkingCheck)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runContainerNetworkingCheck() error {
+	app := lookUpSampleAppByName("golang")
+	if app == nil {
+		return fmt.Errorf("failed to look up the Golang sample app")
+	}
+
+	supported, err := checkSampleAppPrerequisites(*app)
+	if err != nil {
+		return err
+	}
+
+	if !supported {
+		return nil
+	}
+
+	cleanupSetting, err := getCleanupSetting()
+	if err != nil {
+		return err
+	}
+
+	settings := cf.PushSettings{
+		CleanupSetting: cleanupSetting,
+		CleanupTimeout: cleanupTimeoutSetting,
+		NoPing:         noPingSetting,
+		StagingTimeout: stagingTimeoutSetting,
+		StartTimeout:   startTimeoutSetting,
+		TotalTimeout:   totalTimeoutSetting,
+	}
+
+	if settings.Overrides, err = getManifestOverrides(); err != nil {
+		return err
+	}
+
+	directory, err := app.assetFunc()
+	if err != nil {
+		return err
+	}
+
+	frontendName := text.RandomStringWithPrefix(fmt.Sprintf("%s-c2c-frontend-", GonutAppPrefix), 32)
+	backendName := text.RandomStringWithPrefix(fmt.Sprintf("%s-c2c-backend-", GonutAppPrefix), 32)
+
+	report, err := cf.VerifyContainerNetworking(rootContext, "Container Networking", frontendName, backendName, directory, settings)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(summarySetting) {
+	case "quiet":
+		// Nothing to report
+
+	case "short", "oneline":
+		bunt.Printf("Successfully verified container networking, the network policy took CadetBlue{%s} to propagate.\n",
+			cf.HumanReadableDuration(report.PropagationTime()),
+		)
+
+	case "json":
+		out, err := neat.NewOutputProcessor(true, true, &neat.DefaultColorSchema).ToJSON(report.Export())
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(out)
+
+	case "yaml":
+		out, err := neat.ToYAMLString(report.Export())
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(out)
+
+	case "full":
+		headline := bunt.Sprintf("Successfully verified container networking in CadetBlue{%s}",
+			cf.HumanReadableDuration(report.ElapsedTime()),
+		)
+
+		content, err := neat.Table(report.ExportTable(), neat.AlignRight(0))
+		if err != nil {
+			return err
+		}
+
+		neat.Box(os.Stdout, headline, strings.NewReader(content))
+	}
+
+	return nil
+}
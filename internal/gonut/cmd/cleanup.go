@@ -23,7 +23,7 @@ func init() {
 }
 
 func cleanUp(cmd *cobra.Command, args []string) error {
-	apps, err := cf.GetApps()
+	apps, err := cf.GetApps(rootContext)
 	if err != nil {
 		return err
 	}
@@ -32,7 +32,7 @@ func cleanUp(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if err := cf.DeleteApps(appsToClean); err != nil {
+	if err := cf.DeleteApps(rootContext, appsToClean); err != nil {
 		return err
 	}
 
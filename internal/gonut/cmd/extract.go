@@ -37,7 +37,12 @@ var extractCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		baseDir := "."
 
-		for _, sampleApp := range sampleApps {
+		for _, sampleApp := range expandSampleAppVariants(sampleApps) {
+			// Docker image based sample apps do not have any files
+			if sampleApp.assetFunc == nil {
+				continue
+			}
+
 			path := filepath.Join(baseDir, "SampleApps", sampleApp.caption)
 			if err := os.MkdirAll(path, os.FileMode(0755)); err != nil {
 				return err
@@ -0,0 +1,145 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gonvenience/bunt"
+	"github.com/gonvenience/neat"
+	"github.com/homeport/gonut/internal/gonut/cf"
+	"github.com/mitchellh/go-homedir"
+)
+
+func loadSelectedFoundations() ([]cf.Foundation, error) {
+	path, err := homedir.Expand(foundationsConfigSetting)
+	if err != nil {
+		return nil, err
+	}
+
+	foundations, err := cf.LoadFoundations(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return cf.LookUpFoundations(foundations, foundationSetting)
+}
+
+// runSampleAppPushesOnFoundations pushes the sample apps to each of the
+// selected foundations in turn and prints a comparison summary at the end
+func runSampleAppPushesOnFoundations(apps []sampleApp) error {
+	foundations, err := loadSelectedFoundations()
+	if err != nil {
+		return err
+	}
+
+	// Results per foundation, each containing one entry per sample app
+	results := make([][]string, len(foundations))
+	failed := false
+
+	for i, foundation := range foundations {
+		results[i] = make([]string, len(apps))
+
+		// Skip remaining foundations in case gonut was interrupted
+		if rootContext.Err() != nil {
+			for j := range apps {
+				results[i][j] = bunt.Sprintf("DimGray{interrupted}")
+			}
+
+			continue
+		}
+
+		bunt.Printf("Using foundation *%s* (CadetBlue{%s})\n", foundation.Name, foundation.API)
+
+		err := cf.WithFoundation(rootContext, foundation, func() error {
+			return withEphemeralSpaceIfEnabled(func() error {
+				for j, app := range apps {
+					if rootContext.Err() != nil {
+						results[i][j] = bunt.Sprintf("DimGray{interrupted}")
+						continue
+					}
+
+					report, err := runSampleAppPush(app)
+					switch {
+					case err != nil:
+						printGonutError(err)
+						results[i][j] = bunt.Sprintf("Red{failed}")
+						failed = true
+
+					case report == nil:
+						results[i][j] = bunt.Sprintf("DimGray{skipped}")
+
+					default:
+						results[i][j] = bunt.Sprintf("SteelBlue{%s}", cf.HumanReadableDuration(report.ElapsedTime()))
+					}
+				}
+
+				return nil
+			})
+		})
+
+		if err != nil {
+			printGonutError(err)
+			for j := range apps {
+				results[i][j] = bunt.Sprintf("Red{unavailable}")
+			}
+
+			failed = true
+		}
+	}
+
+	if err := printFoundationComparison(foundations, apps, results); err != nil {
+		return err
+	}
+
+	if failed {
+		return fmt.Errorf("sample app push failed on at least one foundation")
+	}
+
+	return nil
+}
+
+func printFoundationComparison(foundations []cf.Foundation, apps []sampleApp, results [][]string) error {
+	header := []string{""}
+	for _, foundation := range foundations {
+		header = append(header, bunt.Sprintf("*%s*", foundation.Name))
+	}
+
+	table := [][]string{header}
+	for j, app := range apps {
+		row := []string{bunt.Sprintf("DimGray{_%s_}", app.caption)}
+		for i := range foundations {
+			row = append(row, results[i][j])
+		}
+
+		table = append(table, row)
+	}
+
+	content, err := neat.Table(table, neat.AlignRight(0))
+	if err != nil {
+		return err
+	}
+
+	neat.Box(os.Stdout, "Foundation comparison", strings.NewReader(content))
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gonvenience/bunt"
+	"github.com/gonvenience/term"
+	"github.com/homeport/gonut/internal/gonut/cf"
+)
+
+var (
+	// rootContext is the context all Cloud Foundry operations run in, it is
+	// cancelled as soon as gonut receives an interrupt or termination signal
+	rootContext, cancelRootContext = context.WithCancel(context.Background())
+
+	interruptSignalLock sync.Mutex
+	interruptSignal     os.Signal
+
+	cleanupTimeoutSetting time.Duration
+)
+
+// HandleInterrupts waits for signals on the provided channel. The first signal
+// cancels all running operations so that the cleanup can take place, which is
+// bound by the cleanup timeout of each cleanup step. A second signal ends gonut
+// immediately.
+func HandleInterrupts(signals <-chan os.Signal) {
+	signal := <-signals
+
+	interruptSignalLock.Lock()
+	interruptSignal = signal
+	interruptSignalLock.Unlock()
+
+	bunt.Printf("\n*Interrupted*, DimGray{cleaning up (press Ctrl+C again to exit immediately)}\n")
+	cancelRootContext()
+
+	<-signals
+
+	term.ShowCursor()
+	os.Exit(interruptExitCode(signal))
+}
+
+// cleanupContext returns a new context that is independent of the root
+// context and bound by the cleanup timeout, so that cleanups still run when
+// gonut is interrupted
+func cleanupContext() (context.Context, context.CancelFunc) {
+	return cf.CleanupContext(cleanupTimeoutSetting)
+}
+
+// exitIfInterrupted leaves gonut with the dedicated exit code in case gonut
+// received an interrupt or termination signal
+func exitIfInterrupted() {
+	interruptSignalLock.Lock()
+	signal := interruptSignal
+	interruptSignalLock.Unlock()
+
+	if signal != nil {
+		term.ShowCursor()
+		os.Exit(interruptExitCode(signal))
+	}
+}
+
+// interruptExitCode follows the shell convention of using 128 plus the signal
+// number as the exit code of a process that was ended by a signal
+func interruptExitCode(signal os.Signal) int {
+	if sig, ok := signal.(syscall.Signal); ok {
+		return 128 + int(sig)
+	}
+
+	return 130
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -33,112 +34,303 @@ import (
 	"github.com/homeport/gonut/internal/gonut/assets"
 	"github.com/homeport/gonut/internal/gonut/cf"
 	"github.com/homeport/pina-golada/pkg/files"
+	"github.com/mitchellh/go-homedir"
 )
 
 // GonutAppPrefix is the prefeix for gonuts applications, it is also used by the
 // cleanup command to decide whether an app is pushed by gonut or not.
 var GonutAppPrefix = "gonut"
 
+// sampleAppVariant is an alternative flavor of a sample app, which serves the
+// same app code but exercises a different code path of the buildpack
+type sampleAppVariant struct {
+	name      string
+	assetFunc func() (files.Directory, error)
+}
+
 type sampleApp struct {
 	caption       string
-	buildpack     string
+	buildpacks    []string
 	command       string
 	aliases       []string
 	appNamePrefix string
 	assetFunc     func() (files.Directory, error)
+
+	// variant is the name of the default flavor of the sample app, variants
+	// lists the alternative flavors that can be selected instead
+	variant  string
+	variants []sampleAppVariant
+
+	// docker sample apps push a Docker image instead of embedded app files
+	docker bool
+
+	// windows sample apps need a Windows stack, which Linux-only foundations
+	// do not offer
+	windows bool
+
+	// crashEndpoint is set for sample apps that exit when /crash is requested
+	crashEndpoint bool
+
+	// servicesEndpoint is set for sample apps that list their bound services on /services
+	servicesEndpoint bool
+
+	// sidecars is set for sample apps that declare sidecars in the manifest and
+	// forward /sidecar to them
+	sidecars bool
+
+	// logEndpoint is set for sample apps that print the marker given to /log
+	logEndpoint bool
+
+	// websocketEndpoint is set for sample apps that echo WebSocket messages on
+	// /ws and report the protocol of the request on /protocol
+	websocketEndpoint bool
+
+	// runtimeEndpoint is set for sample apps that report their runtime on /runtime
+	runtimeEndpoint bool
 }
 
 var (
 	deleteSetting  string
 	summarySetting string
 	noPingSetting  bool
+
+	foundationSetting        []string
+	foundationsConfigSetting string
+
+	ephemeralSpaceSetting      bool
+	ephemeralSpaceOrgSetting   string
+	ephemeralSpaceQuotaSetting string
+
+	stagingTimeoutSetting time.Duration
+	startTimeoutSetting   time.Duration
+	totalTimeoutSetting   time.Duration
+
+	dockerImageSetting    string
+	dockerUsernameSetting string
+
+	manifestOverridesSetting string
+	memorySetting            string
+	diskQuotaSetting         string
+	instancesSetting         int
+	stackSetting             string
+	buildpackSetting         string
+	healthCheckTypeSetting   string
+	envSetting               []string
+
+	crashRecoverySetting          bool
+	crashRecoveryThresholdSetting time.Duration
+
+	lifecycleSetting []string
+
+	rollingDeploymentSetting bool
+
+	withServiceSetting string
+
+	sshSetting     bool
+	runTaskSetting bool
+
+	logStreamingSetting   bool
+	containerStatsSetting bool
+
+	loadSetting      string
+	protocolsSetting bool
+
+	variantSetting  string
+	variantsSetting bool
 )
 
 var sampleApps = []sampleApp{
 	{
-		caption:       "Golang",
-		command:       "golang",
-		buildpack:     "go_buildpack",
-		aliases:       []string{"go"},
-		appNamePrefix: fmt.Sprintf("%s-golang-app-", GonutAppPrefix),
-		assetFunc:     assets.Provider.GoSampleApp,
+		caption:           "Golang",
+		command:           "golang",
+		buildpacks:        []string{"go_buildpack"},
+		aliases:           []string{"go"},
+		appNamePrefix:     fmt.Sprintf("%s-golang-app-", GonutAppPrefix),
+		assetFunc:         assets.Provider.GoSampleApp,
+		runtimeEndpoint:   true,
+		crashEndpoint:     true,
+		servicesEndpoint:  true,
+		logEndpoint:       true,
+		websocketEndpoint: true,
 	},
 
 	{
-		caption:       "Python",
-		command:       "python",
-		buildpack:     "python_buildpack",
-		aliases:       []string{},
-		appNamePrefix: fmt.Sprintf("%s-python-app-", GonutAppPrefix),
-		assetFunc:     assets.Provider.PythonSampleApp,
+		caption:          "Golang Modules",
+		command:          "golang-modules",
+		buildpacks:       []string{"go_buildpack"},
+		aliases:          []string{"go-modules"},
+		appNamePrefix:    fmt.Sprintf("%s-golang-modules-app-", GonutAppPrefix),
+		assetFunc:        assets.Provider.GoModulesSampleApp,
+		runtimeEndpoint:  true,
+		crashEndpoint:    true,
+		servicesEndpoint: true,
+		logEndpoint:      true,
 	},
 
 	{
-		caption:       "PHP",
-		command:       "php",
-		buildpack:     "php_buildpack",
-		aliases:       []string{},
-		appNamePrefix: fmt.Sprintf("%s-php-app-", GonutAppPrefix),
-		assetFunc:     assets.Provider.PHPSampleApp,
+		caption:          "Python",
+		command:          "python",
+		buildpacks:       []string{"python_buildpack"},
+		aliases:          []string{},
+		appNamePrefix:    fmt.Sprintf("%s-python-app-", GonutAppPrefix),
+		assetFunc:        assets.Provider.PythonSampleApp,
+		runtimeEndpoint:  true,
+		crashEndpoint:    true,
+		servicesEndpoint: true,
+		logEndpoint:      true,
+		variant:          "pip",
+		variants: []sampleAppVariant{
+			{name: "pipenv", assetFunc: assets.Provider.PythonPipenvSampleApp},
+			{name: "conda", assetFunc: assets.Provider.PythonCondaSampleApp},
+		},
+	},
+
+	{
+		caption:         "PHP",
+		command:         "php",
+		buildpacks:      []string{"php_buildpack"},
+		aliases:         []string{},
+		appNamePrefix:   fmt.Sprintf("%s-php-app-", GonutAppPrefix),
+		assetFunc:       assets.Provider.PHPSampleApp,
+		runtimeEndpoint: true,
 	},
 
 	{
 		caption:       "Staticfile",
 		command:       "staticfile",
-		buildpack:     "staticfile_buildpack",
+		buildpacks:    []string{"staticfile_buildpack"},
 		aliases:       []string{"static"},
 		appNamePrefix: fmt.Sprintf("%s-staticfile-app-", GonutAppPrefix),
 		assetFunc:     assets.Provider.StaticfileSampleApp,
 	},
 
 	{
-		caption:       "Swift",
-		command:       "swift",
-		buildpack:     "swift_buildpack",
-		aliases:       []string{},
-		appNamePrefix: fmt.Sprintf("%s-swift-app-", GonutAppPrefix),
-		assetFunc:     assets.Provider.SwiftSampleApp,
+		caption:         "Swift",
+		command:         "swift",
+		buildpacks:      []string{"swift_buildpack"},
+		aliases:         []string{},
+		appNamePrefix:   fmt.Sprintf("%s-swift-app-", GonutAppPrefix),
+		assetFunc:       assets.Provider.SwiftSampleApp,
+		runtimeEndpoint: true,
+	},
+
+	{
+		caption:          "NodeJS",
+		command:          "nodejs",
+		buildpacks:       []string{"nodejs_buildpack"},
+		aliases:          []string{"node"},
+		appNamePrefix:    fmt.Sprintf("%s-nodejs-app-", GonutAppPrefix),
+		assetFunc:        assets.Provider.NodeJSSampleApp,
+		runtimeEndpoint:  true,
+		crashEndpoint:    true,
+		servicesEndpoint: true,
+		logEndpoint:      true,
+		variant:          "npm",
+		variants: []sampleAppVariant{
+			{name: "yarn", assetFunc: assets.Provider.NodeJSYarnSampleApp},
+		},
+	},
+
+	{
+		caption:          "Ruby",
+		command:          "ruby",
+		buildpacks:       []string{"ruby_buildpack"},
+		appNamePrefix:    fmt.Sprintf("%s-ruby-sinatra-app-", GonutAppPrefix),
+		assetFunc:        assets.Provider.RubySampleApp,
+		runtimeEndpoint:  true,
+		crashEndpoint:    true,
+		servicesEndpoint: true,
+		logEndpoint:      true,
+	},
+
+	{
+		caption:         ".NET",
+		command:         "dotnet",
+		buildpacks:      []string{"dotnet-core"},
+		appNamePrefix:   fmt.Sprintf("%s-dotnet-app-", GonutAppPrefix),
+		assetFunc:       assets.Provider.DotNetSampleApp,
+		runtimeEndpoint: true,
+	},
+
+	{
+		caption:          "Binary",
+		command:          "binary",
+		buildpacks:       []string{"binary_buildpack"},
+		appNamePrefix:    fmt.Sprintf("%s-binary-app-", GonutAppPrefix),
+		assetFunc:        assets.Provider.BinarySampleApp,
+		runtimeEndpoint:  true,
+		crashEndpoint:    true,
+		servicesEndpoint: true,
+		logEndpoint:      true,
 	},
 
 	{
-		caption:       "NodeJS",
-		command:       "nodejs",
-		buildpack:     "nodejs_buildpack",
-		aliases:       []string{"node"},
-		appNamePrefix: fmt.Sprintf("%s-nodejs-app-", GonutAppPrefix),
-		assetFunc:     assets.Provider.NodeJSSampleApp,
+		caption:         "Java",
+		command:         "java",
+		buildpacks:      []string{"java_buildpack"},
+		appNamePrefix:   fmt.Sprintf("%s-java-app-", GonutAppPrefix),
+		assetFunc:       assets.Provider.JavaSampleApp,
+		runtimeEndpoint: true,
+		variant:         "jar",
+		variants: []sampleAppVariant{
+			{name: "war", assetFunc: assets.Provider.JavaWarSampleApp},
+		},
 	},
 
 	{
-		caption:       "Ruby",
-		command:       "ruby",
-		buildpack:     "ruby_buildpack",
-		appNamePrefix: fmt.Sprintf("%s-ruby-sinatra-app-", GonutAppPrefix),
-		assetFunc:     assets.Provider.RubySampleApp,
+		caption:         "R",
+		command:         "r",
+		buildpacks:      []string{"r_buildpack"},
+		appNamePrefix:   fmt.Sprintf("%s-r-app-", GonutAppPrefix),
+		assetFunc:       assets.Provider.RSampleApp,
+		runtimeEndpoint: true,
 	},
 
 	{
-		caption:       ".NET",
-		command:       "dotnet",
-		buildpack:     "dotnet-core",
-		appNamePrefix: fmt.Sprintf("%s-dotnet-app-", GonutAppPrefix),
-		assetFunc:     assets.Provider.DotNetSampleApp,
+		caption:       "Nginx",
+		command:       "nginx",
+		buildpacks:    []string{"nginx_buildpack"},
+		appNamePrefix: fmt.Sprintf("%s-nginx-app-", GonutAppPrefix),
+		assetFunc:     assets.Provider.NginxSampleApp,
 	},
 
 	{
-		caption:       "Binary",
-		command:       "binary",
-		buildpack:     "binary_buildpack",
-		appNamePrefix: fmt.Sprintf("%s-binary-app-", GonutAppPrefix),
-		assetFunc:     assets.Provider.BinarySampleApp,
+		caption:       "HWC",
+		command:       "hwc",
+		buildpacks:    []string{"hwc_buildpack"},
+		appNamePrefix: fmt.Sprintf("%s-hwc-app-", GonutAppPrefix),
+		assetFunc:     assets.Provider.HWCSampleApp,
+		windows:       true,
 	},
 
 	{
-		caption:       "Java",
-		command:       "java",
-		buildpack:     "java_buildpack",
-		appNamePrefix: fmt.Sprintf("%s-java-app-", GonutAppPrefix),
-		assetFunc:     assets.Provider.JavaSampleApp,
+		caption:         "Sidecar",
+		command:         "sidecar",
+		buildpacks:      []string{"nodejs_buildpack"},
+		appNamePrefix:   fmt.Sprintf("%s-sidecar-app-", GonutAppPrefix),
+		assetFunc:       assets.Provider.SidecarSampleApp,
+		runtimeEndpoint: true,
+		sidecars:        true,
+	},
+
+	{
+		caption:           "Multi-Buildpack",
+		command:           "multi-buildpack",
+		buildpacks:        []string{"nodejs_buildpack", "go_buildpack"},
+		appNamePrefix:     fmt.Sprintf("%s-multi-buildpack-app-", GonutAppPrefix),
+		assetFunc:         assets.Provider.GoSampleApp,
+		runtimeEndpoint:   true,
+		crashEndpoint:     true,
+		servicesEndpoint:  true,
+		logEndpoint:       true,
+		websocketEndpoint: true,
+	},
+
+	{
+		caption:       "Docker",
+		command:       "docker",
+		appNamePrefix: fmt.Sprintf("%s-docker-app-", GonutAppPrefix),
+		docker:        true,
 	},
 }
 
@@ -155,29 +347,124 @@ func init() {
 	pushCmd.PersistentFlags().StringVarP(&deleteSetting, "delete", "d", "always", "Delete application after push: always, never, on-success")
 	pushCmd.PersistentFlags().StringVarP(&summarySetting, "summary", "s", "short", "Push summary detail level: quiet, short, full")
 	pushCmd.PersistentFlags().BoolVarP(&noPingSetting, "no-ping", "p", false, "Do not ping application after push")
+	pushCmd.PersistentFlags().StringSliceVarP(&foundationSetting, "foundation", "f", []string{}, "Comma separated list of named foundations to push to one after another")
+	pushCmd.PersistentFlags().StringVar(&foundationsConfigSetting, "foundations-config", "~/.gonut/foundations.yml", "Path to the foundations configuration file")
+	pushCmd.PersistentFlags().DurationVar(&cleanupTimeoutSetting, "cleanup-timeout", 2*time.Minute, "Maximum time each cleanup step may take, also after gonut was interrupted (zero means no limit)")
+	pushCmd.PersistentFlags().DurationVar(&stagingTimeoutSetting, "staging-timeout", 0, "Maximum time the staging of the app may take (zero means no limit)")
+	pushCmd.PersistentFlags().DurationVar(&startTimeoutSetting, "start-timeout", 0, "Maximum time the start of the app may take (zero means no limit)")
+	pushCmd.PersistentFlags().DurationVar(&totalTimeoutSetting, "total-timeout", 0, "Maximum time the whole push of the app may take (zero means no limit)")
+	pushCmd.PersistentFlags().StringVar(&dockerImageSetting, "docker-image", "cloudfoundry/diego-docker-app:latest", "Docker image to be used for the Docker sample app")
+	pushCmd.PersistentFlags().StringVar(&dockerUsernameSetting, "docker-username", "", "Registry user name for the Docker image (password is read from CF_DOCKER_PASSWORD)")
+	pushCmd.PersistentFlags().StringVar(&manifestOverridesSetting, "manifest-overrides", "", "Path to a YAML file with sample app manifest overrides")
+	pushCmd.PersistentFlags().StringVar(&memorySetting, "memory", "", "Memory limit of the sample app, for example 256M")
+	pushCmd.PersistentFlags().StringVar(&diskQuotaSetting, "disk", "", "Disk limit of the sample app, for example 1G")
+	pushCmd.PersistentFlags().IntVar(&instancesSetting, "instances", 0, "Number of instances of the sample app, each instance is verified individually")
+	pushCmd.PersistentFlags().StringVar(&stackSetting, "stack", "", "Stack to be used for the sample app, for example cflinuxfs4")
+	pushCmd.PersistentFlags().StringVar(&buildpackSetting, "buildpack", "", "Buildpack name or URL to be used for the sample app")
+	pushCmd.PersistentFlags().StringVar(&healthCheckTypeSetting, "health-check-type", "", "Health check type of the sample app: port, process, http")
+	pushCmd.PersistentFlags().StringArrayVar(&envSetting, "env", []string{}, "Environment variable for the sample app in the form KEY=VALUE (can be used multiple times)")
+	pushCmd.PersistentFlags().BoolVar(&crashRecoverySetting, "crash-recovery", false, "Crash the app after the push and verify that it recovers")
+	pushCmd.PersistentFlags().DurationVar(&crashRecoveryThresholdSetting, "crash-recovery-threshold", time.Minute, "Maximum time the app may take to recover from a crash")
+	pushCmd.PersistentFlags().StringSliceVar(&lifecycleSetting, "lifecycle", []string{}, "Comma separated list of operations to run after the push: restart, restage, scale")
+	pushCmd.PersistentFlags().BoolVar(&rollingDeploymentSetting, "rolling-deployment", false, "Redeploy the app with the rolling strategy and verify that it has no downtime")
+	pushCmd.PersistentFlags().StringVar(&withServiceSetting, "with-service", "", "Create and bind a marketplace service to the app, in the form <offering>:<plan>")
+	pushCmd.PersistentFlags().BoolVar(&sshSetting, "ssh", false, "Run a command in the app container using cf ssh, skipped if SSH is disabled")
+	pushCmd.PersistentFlags().BoolVar(&runTaskSetting, "run-task", false, "Run a one-off task on the droplet of the app and wait for it to finish")
+	pushCmd.PersistentFlags().BoolVar(&logStreamingSetting, "log-streaming", false, "Measure the time it takes until a log line of the app shows up in the log stream")
+	pushCmd.PersistentFlags().BoolVar(&containerStatsSetting, "container-stats", false, "Verify and report the CPU, memory, and disk usage of the app container")
+	pushCmd.PersistentFlags().StringVar(&loadSetting, "load", "", "Send steady traffic to the app after the push, in the form <duration>@<rate>rps, for example 30s@50rps")
+	pushCmd.PersistentFlags().BoolVar(&protocolsSetting, "protocols", false, "Check WebSocket upgrades through the router and whether HTTP/2 is used from clients to the router and from the router to the app")
+	pushCmd.PersistentFlags().BoolVar(&ephemeralSpaceSetting, "ephemeral-space", false, "Push into a temporary space that is deleted after the run")
+	pushCmd.PersistentFlags().StringVar(&ephemeralSpaceOrgSetting, "ephemeral-space-org", "", "Org to create the ephemeral space in (default is the targeted org)")
+	pushCmd.PersistentFlags().StringVar(&ephemeralSpaceQuotaSetting, "ephemeral-space-quota", "", "Name of an existing space quota to assign to the ephemeral space")
 
 	for _, sampleApp := range sampleApps {
-		pushCmd.AddCommand(&cobra.Command{
+		sampleAppCmd := &cobra.Command{
 			Use:     sampleApp.command,
 			Aliases: sampleApp.aliases,
 			Short:   fmt.Sprintf("Push a %s sample app to Cloud Foundry", sampleApp.caption),
 			Long:    fmt.Sprintf(`Push a %s sample app to Cloud Foundry. The application will be deleted after it was pushed successfully.`, sampleApp.caption),
 			Run:     genericCommandFunc,
-		})
+		}
+
+		if len(sampleApp.variants) > 0 {
+			sampleAppCmd.Flags().StringVar(&variantSetting, "variant", "", fmt.Sprintf("Variant of the sample app: %s (default), %s", sampleApp.variant, strings.Join(sampleApp.variantNames()[1:], ", ")))
+		}
+
+		pushCmd.AddCommand(sampleAppCmd)
 	}
 
-	pushCmd.AddCommand(&cobra.Command{
+	allCmd := &cobra.Command{
 		Use:   "all",
 		Short: "Pushes all available sample apps to Cloud Foundry",
 		Long:  `Pushes all available sample apps to Cloud Foundry. Each application will be deleted after it was pushed successfully.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			for _, sampleApp := range sampleApps {
-				if err := runSampleAppPush(sampleApp); err != nil {
-					ExitGonut(err)
-				}
+			apps := sampleApps
+			if variantsSetting {
+				apps = expandSampleAppVariants(sampleApps)
+			}
+
+			if err := runSampleAppPushes(apps); err != nil {
+				ExitGonut(err)
 			}
 		},
-	})
+	}
+
+	allCmd.Flags().BoolVar(&variantsSetting, "variants", false, "Push all variants of each sample app instead of only the default one")
+	pushCmd.AddCommand(allCmd)
+}
+
+// variantNames returns the names of all flavors of the sample app, starting
+// with the default one
+func (app sampleApp) variantNames() []string {
+	result := []string{app.variant}
+	for _, variant := range app.variants {
+		result = append(result, variant.name)
+	}
+
+	return result
+}
+
+// selectVariant returns the sample app in the flavor with the given name, the
+// default flavor is used if the name is empty
+func (app sampleApp) selectVariant(name string) (sampleApp, error) {
+	if len(name) == 0 || name == app.variant {
+		return app, nil
+	}
+
+	for _, variant := range app.variants {
+		if variant.name == name {
+			app.caption = fmt.Sprintf("%s (%s)", app.caption, variant.name)
+			app.appNamePrefix = fmt.Sprintf("%s-%s-%s-app-", GonutAppPrefix, app.command, variant.name)
+			app.assetFunc = variant.assetFunc
+			app.variant = variant.name
+			app.variants = nil
+			return app, nil
+		}
+	}
+
+	return app, fmt.Errorf("unsupported variant %s of the %s sample app, available variants are: %s",
+		name,
+		app.caption,
+		strings.Join(app.variantNames(), ", "),
+	)
+}
+
+// expandSampleAppVariants returns the list of sample apps where each one is
+// followed by all of its variants
+func expandSampleAppVariants(apps []sampleApp) []sampleApp {
+	result := []sampleApp{}
+	for _, app := range apps {
+		result = append(result, app)
+
+		for _, variant := range app.variants {
+			if variantApp, err := app.selectVariant(variant.name); err == nil {
+				result = append(result, variantApp)
+			}
+		}
+	}
+
+	return result
 }
 
 func lookUpSampleAppByName(name string) *sampleApp {
@@ -191,57 +478,324 @@ func lookUpSampleAppByName(name string) *sampleApp {
 }
 
 func genericCommandFunc(cmd *cobra.Command, args []string) {
-	sampleApp := lookUpSampleAppByName(cmd.Use)
-	if sampleApp == nil {
+	app := lookUpSampleAppByName(cmd.Use)
+	if app == nil {
 		ExitGonut("failed to detect which sample app is to be tested")
 	}
 
-	if err := runSampleAppPush(*sampleApp); err != nil {
+	selected, err := app.selectVariant(variantSetting)
+	if err != nil {
+		ExitGonut(err)
+	}
+
+	if err := runSampleAppPushes([]sampleApp{selected}); err != nil {
 		ExitGonut(err)
 	}
 }
 
-func runSampleAppPush(app sampleApp) error {
-	hasBuildpack, err := cf.HasBuildpack(app.buildpack)
+func runSampleAppPushes(apps []sampleApp) error {
+	if len(foundationSetting) > 0 {
+		return runSampleAppPushesOnFoundations(apps)
+	}
+
+	return withEphemeralSpaceIfEnabled(func() error {
+		for _, app := range apps {
+			if _, err := runSampleAppPush(app); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// withEphemeralSpaceIfEnabled runs the given function in a newly created space
+// if the ephemeral space setting is enabled. The space is deleted afterwards,
+// regardless of the outcome and also in case gonut is interrupted.
+func withEphemeralSpaceIfEnabled(f func() error) error {
+	if !ephemeralSpaceSetting {
+		return f()
+	}
+
+	spaceName := text.RandomStringWithPrefix(fmt.Sprintf("%s-space-", GonutAppPrefix), 32)
+	space, err := cf.CreateEphemeralSpace(rootContext, ephemeralSpaceOrgSetting, spaceName, ephemeralSpaceQuotaSetting, cleanupTimeoutSetting)
 	if err != nil {
 		return err
 	}
 
-	// Skip sample app push if desired buildpack is unavailable
-	if !hasBuildpack {
+	defer func() {
+		ctx, cancel := cleanupContext()
+		defer cancel()
+
+		if err := space.Delete(ctx); err != nil {
+			printGonutError(err)
+		}
+	}()
+
+	return f()
+}
+
+// checkSampleAppPrerequisites returns true if the Cloud Foundry offers what is
+// needed to push the sample app, otherwise it notes why the push is skipped
+func checkSampleAppPrerequisites(app sampleApp) (bool, error) {
+	if app.docker {
+		enabled, err := cf.IsFeatureFlagEnabled(rootContext, "diego_docker")
+		if err != nil {
+			return false, err
+		}
+
+		// Skip sample app push if Docker support is disabled
+		if !enabled {
+			bunt.Printf("Skipping push of *%s* sample app, because feature flag DarkSeaGreen{%s} is disabled.\n",
+				app.caption,
+				"diego_docker",
+			)
+		}
+
+		return enabled, nil
+	}
+
+	overrides, err := getManifestOverrides()
+	if err != nil {
+		return false, err
+	}
+
+	// Skip sample app push if the foundation only offers Linux stacks
+	if app.windows && len(overrides.Stack) == 0 {
+		stack, err := getWindowsStack()
+		if err != nil {
+			return false, err
+		}
+
+		if len(stack) == 0 {
+			bunt.Printf("Skipping push of *%s* sample app, because there is no DarkSeaGreen{%s} available.\n",
+				app.caption,
+				"Windows stack",
+			)
+
+			return false, nil
+		}
+	}
+
+	// Buildpacks that are referenced by URL do not need to be installed
+	buildpacks := app.buildpacks
+	if len(overrides.Buildpack) > 0 {
+		if strings.Contains(overrides.Buildpack, "://") {
+			return true, nil
+		}
+
+		buildpacks = []string{overrides.Buildpack}
+	}
+
+	missing, err := cf.MissingBuildpacks(rootContext, buildpacks)
+	if err != nil {
+		return false, err
+	}
+
+	// Skip sample app push if any of the desired buildpacks is unavailable
+	if len(missing) > 0 {
 		bunt.Printf("Skipping push of *%s* sample app, because there is no DarkSeaGreen{%s} installed.\n",
 			app.caption,
-			app.buildpack,
+			strings.Join(missing, ", "),
 		)
+	}
 
-		return nil
+	return len(missing) == 0, nil
+}
+
+// getWindowsStack returns the name of a Windows stack of the Cloud Foundry, or
+// an empty string if there is none
+func getWindowsStack() (string, error) {
+	stacks, err := cf.GetStacks(rootContext)
+	if err != nil {
+		return "", err
 	}
 
-	var cleanupSetting cf.AppCleanupSetting
+	return cf.WindowsStack(stacks), nil
+}
+
+// getManifestOverrides combines the overrides from the configuration file with
+// the ones from the command-line flags, where the flags take precedence
+func getManifestOverrides() (cf.ManifestOverrides, error) {
+	var overrides cf.ManifestOverrides
+
+	if len(manifestOverridesSetting) > 0 {
+		path, err := homedir.Expand(manifestOverridesSetting)
+		if err != nil {
+			return overrides, err
+		}
+
+		if overrides, err = cf.LoadManifestOverrides(path); err != nil {
+			return overrides, err
+		}
+	}
+
+	env := map[string]string{}
+	for _, entry := range envSetting {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return overrides, fmt.Errorf("unsupported environment variable setting %s, expected KEY=VALUE", entry)
+		}
+
+		env[parts[0]] = parts[1]
+	}
+
+	return overrides.Merge(cf.ManifestOverrides{
+		Memory:          memorySetting,
+		DiskQuota:       diskQuotaSetting,
+		Instances:       instancesSetting,
+		Stack:           stackSetting,
+		Buildpack:       buildpackSetting,
+		HealthCheckType: healthCheckTypeSetting,
+		Env:             env,
+	}), nil
+}
+
+func getCleanupSetting() (cf.AppCleanupSetting, error) {
 	switch deleteSetting {
 	case "always":
-		cleanupSetting = cf.Always
+		return cf.Always, nil
 
 	case "never":
-		cleanupSetting = cf.Never
+		return cf.Never, nil
 
 	case "on-success":
-		cleanupSetting = cf.OnSuccess
+		return cf.OnSuccess, nil
 
 	default:
-		return fmt.Errorf("unsupported delete setting: %s", deleteSetting)
+		return cf.Never, fmt.Errorf("unsupported delete setting: %s", deleteSetting)
 	}
+}
 
-	appName := text.RandomStringWithPrefix(app.appNamePrefix, 32)
+func runSampleAppPush(app sampleApp) (*cf.PushReport, error) {
+	supported, err := checkSampleAppPrerequisites(app)
+	if err != nil {
+		return nil, err
+	}
+
+	if !supported {
+		return nil, nil
+	}
 
-	directory, err := app.assetFunc()
+	cleanupSetting, err := getCleanupSetting()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	report, err := cf.PushApp(app.caption, appName, directory, cleanupSetting, noPingSetting)
+	appName := text.RandomStringWithPrefix(app.appNamePrefix, 32)
+
+	settings := cf.PushSettings{
+		CleanupSetting: cleanupSetting,
+		CleanupTimeout: cleanupTimeoutSetting,
+		NoPing:         noPingSetting,
+		StagingTimeout: stagingTimeoutSetting,
+		StartTimeout:   startTimeoutSetting,
+		TotalTimeout:   totalTimeoutSetting,
+
+		CrashRecovery:          crashRecoverySetting && app.crashEndpoint,
+		CrashRecoveryThreshold: crashRecoveryThresholdSetting,
+
+		RollingDeployment: rollingDeploymentSetting,
+		SSHCheck:          sshSetting,
+		RunTask:           runTaskSetting,
+		Sidecars:          app.sidecars,
+		LogStreaming:      logStreamingSetting && app.logEndpoint,
+		ContainerStats:    containerStatsSetting,
+		Protocols:         protocolsSetting && app.websocketEndpoint,
+		RuntimeInfo:       app.runtimeEndpoint,
+	}
+
+	for _, operation := range lifecycleSetting {
+		switch operation = strings.ToLower(strings.TrimSpace(operation)); operation {
+		case cf.Restart, cf.Restage, cf.Scale:
+			settings.LifecycleOperations = append(settings.LifecycleOperations, operation)
+
+		default:
+			return nil, fmt.Errorf("unsupported lifecycle operation: %s", operation)
+		}
+	}
+
+	if len(loadSetting) > 0 {
+		if settings.Load, err = cf.ParseLoadSettings(loadSetting); err != nil {
+			return nil, err
+		}
+
+		if noPingSetting {
+			bunt.Printf("Skipping load probe of *%s* sample app, because pinging the app is disabled.\n",
+				app.caption,
+			)
+		}
+	}
+
+	if len(withServiceSetting) > 0 {
+		parts := strings.Split(withServiceSetting, ":")
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, fmt.Errorf("unsupported service setting %s, expected <offering>:<plan>", withServiceSetting)
+		}
+
+		if app.servicesEndpoint {
+			settings.ServiceOffering, settings.ServicePlan = parts[0], parts[1]
+
+		} else {
+			bunt.Printf("Skipping service binding check of *%s* sample app, because it has no services endpoint.\n",
+				app.caption,
+			)
+		}
+	}
+
+	if protocolsSetting && !app.websocketEndpoint {
+		bunt.Printf("Skipping protocol checks of *%s* sample app, because it has no WebSocket endpoint.\n",
+			app.caption,
+		)
+	}
+
+	if logStreamingSetting && !app.logEndpoint {
+		bunt.Printf("Skipping log streaming check of *%s* sample app, because it has no log endpoint.\n",
+			app.caption,
+		)
+	}
+
+	if crashRecoverySetting && !app.crashEndpoint {
+		bunt.Printf("Skipping crash recovery check of *%s* sample app, because it has no crash endpoint.\n",
+			app.caption,
+		)
+	}
+
+	if settings.Overrides, err = getManifestOverrides(); err != nil {
+		return nil, err
+	}
+
+	var directory files.Directory
+	if app.docker {
+		settings.DockerImage = dockerImageSetting
+		settings.DockerUsername = dockerUsernameSetting
+
+		// Buildpack and stack do not apply to Docker image based apps
+		settings.Overrides.Buildpack = ""
+		settings.Overrides.Stack = ""
+
+	} else {
+		if directory, err = app.assetFunc(); err != nil {
+			return nil, err
+		}
+
+		// Windows sample apps are pushed to the Windows stack of the foundation,
+		// unless a specific stack was requested
+		if app.windows && len(settings.Overrides.Stack) == 0 {
+			if settings.Overrides.Stack, err = getWindowsStack(); err != nil {
+				return nil, err
+			}
+		}
+
+		// Buildpack chains are pushed with one buildpack flag per buildpack
+		if len(app.buildpacks) > 1 && len(settings.Overrides.Buildpack) == 0 {
+			settings.Buildpacks = app.buildpacks
+		}
+	}
+
+	report, err := cf.PushApp(rootContext, app.caption, appName, directory, settings)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	switch strings.ToLower(summarySetting) {
@@ -257,7 +811,7 @@ func runSampleAppPush(app sampleApp) error {
 	case "json":
 		out, err := neat.NewOutputProcessor(true, true, &neat.DefaultColorSchema).ToJSON(report.Export())
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		fmt.Println(out)
@@ -265,7 +819,7 @@ func runSampleAppPush(app sampleApp) error {
 	case "yaml":
 		out, err := neat.ToYAMLString(report.Export())
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		fmt.Println(out)
@@ -278,11 +832,72 @@ func runSampleAppPush(app sampleApp) error {
 
 		content, err := neat.Table(report.ExportTable(), neat.AlignRight(0))
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		neat.Box(os.Stdout, headline, strings.NewReader(content))
+
+		for i := range report.Lifecycle {
+			lifecycle := report.Lifecycle[i]
+			headline := bunt.Sprintf("Successfully ran *%s* of *%s* sample app in CadetBlue{%s}",
+				lifecycle.Operation,
+				app.caption,
+				cf.HumanReadableDuration(lifecycle.ElapsedTime()),
+			)
+
+			content, err := neat.Table(lifecycle.ExportTable(), neat.AlignRight(0))
+			if err != nil {
+				return nil, err
+			}
+
+			neat.Box(os.Stdout, headline, strings.NewReader(content))
+		}
+
+		if load := report.Load; load != nil {
+			headline := bunt.Sprintf("Successfully sent *%d* requests to *%s* sample app at CadetBlue{%.1f rps}",
+				load.Requests,
+				app.caption,
+				load.Throughput(),
+			)
+
+			content, err := neat.Table(load.ExportTable(), neat.AlignRight(0))
+			if err != nil {
+				return nil, err
+			}
+
+			neat.Box(os.Stdout, headline, strings.NewReader(content))
+		}
+
+		if service := report.Service; service != nil {
+			headline := bunt.Sprintf("Successfully bound *%s* service with plan *%s* to *%s* sample app",
+				service.Offering,
+				service.Plan,
+				app.caption,
+			)
+
+			content, err := neat.Table(service.ExportTable(), neat.AlignRight(0))
+			if err != nil {
+				return nil, err
+			}
+
+			neat.Box(os.Stdout, headline, strings.NewReader(content))
+		}
+
+		if rolling := report.RollingDeployment; rolling != nil {
+			headline := bunt.Sprintf("Successfully ran *%s* of *%s* sample app in CadetBlue{%s}",
+				rolling.Operation,
+				app.caption,
+				cf.HumanReadableDuration(rolling.ElapsedTime()),
+			)
+
+			content, err := neat.Table(rolling.ExportTable(), neat.AlignRight(0))
+			if err != nil {
+				return nil, err
+			}
+
+			neat.Box(os.Stdout, headline, strings.NewReader(content))
+		}
 	}
 
-	return nil
+	return report, nil
 }
@@ -48,12 +48,21 @@ include arbitrary sample app data in the application binary.`),
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
+		exitIfInterrupted()
 		os.Exit(1)
 	}
+
+	exitIfInterrupted()
 }
 
 // ExitGonut leaves gonut in case of an unresolvable error situation
 func ExitGonut(reason interface{}) {
+	printGonutError(reason)
+	exitIfInterrupted()
+	os.Exit(1)
+}
+
+func printGonutError(reason interface{}) {
 	switch typed := reason.(type) {
 	case *nok.ErrorWithDetails:
 		bunt.Printf("*Error:* _%s_\n", typed.Caption)
@@ -62,6 +71,4 @@ func ExitGonut(reason interface{}) {
 	default:
 		fmt.Println(reason)
 	}
-
-	os.Exit(1)
 }